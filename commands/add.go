@@ -3,11 +3,13 @@ package sshmkr_commands
 import (
 	"strings"
 	"sshmkr/reader"
+	"sshmkr/templates"
 )
 
 // Adds a new host config to a config file
-// Returns the new config file contents
-func AddTemplatedConfig(mainHeader string, subHeader string, templateString string, fileContents []byte) string {
+// The new host is placed at the end of the given sub header's section, which is created if it does not exist yet
+// A host cannot be added if any of its names is already used by another host
+func AddTemplatedConfig(mainHeader string, subHeader string, templateString string, files *sshmkr_templates.ConfigFiles) error {
 	/*
 	*	The logic behind this is that we are adding in new config based on a passed template.
 	* 	The user will pass in three flags  (two being config headers) and the name of the template used.
@@ -15,30 +17,74 @@ func AddTemplatedConfig(mainHeader string, subHeader string, templateString stri
 	*	to use the default). Once done, the new config will be put into the config file.
 	*
 	*/
-	
-	foundHeader := false
-	foundSubHeader := false
-	fileContentsArray := strings.Split(string(fileContents), "\n")
-	
-	for currIndex, currLine := range fileContentsArray {
-		if foundHeader == true && foundSubHeader == true {
-			// We will add the new config once we run into another header type
-			if strings.Contains(currLine, sshmkr_reader.SUB_HEADER_IND) {
-				// This means the previous line is the line that we are interested in replacing
-				fileContentsArray[currIndex-1] = templateString
-				break
-			} else if currIndex + 1 >= len(fileContentsArray) {
-				// We reached the end of the file, so we just place the new contents here
-				fileContentsArray[currIndex] = templateString
-				break
-			}
-		} else if currLine == mainHeader {
-			foundHeader = true
-		} else if foundHeader == true && currLine == subHeader {
-			foundSubHeader = true
+
+	newEntries := ParseTemplatedConfig(templateString)
+	for _, currEntry := range newEntries {
+		if err := checkNamesNotUsed(currEntry, nil, files); err != nil {
+			return err
 		}
 	}
 
-	newContents := strings.Join(fileContentsArray, "\n")
-	return newContents
+	doc, subHeaderIndex, err := findSelectedSubHeader(mainHeader, subHeader, files)
+	if err != nil {
+		return err
+	}
+	InsertIntoSection(subHeaderIndex, newEntries, doc)
+	return nil
+}
+
+// Helper method that checks that none of the names of a Host block are used by another host that is not commented out
+// The host that is being changed (if any) is skipped, so a host can keep its own names
+func checkNamesNotUsed(entry *sshmkr_templates.ConfigEntry, changedHost *sshmkr_templates.ConfigEntry, files *sshmkr_templates.ConfigFiles) error {
+	if entry.Kind != sshmkr_templates.HostLine || entry.IsCommented() {
+		return nil
+	}
+	for _, currPattern := range entry.GetPatterns() {
+		if _, _, usedBy := files.FindBlock(currPattern, false); usedBy != nil && usedBy != changedHost {
+			return sshmkr_templates.NewConfigError(sshmkr_templates.ErrDuplicateHost, "The name", currPattern, "is already used by host", usedBy.GetName(), "!")
+		}
+	}
+	return nil
+}
+
+// Helper method that finds the sub header that was picked during the header selection
+// Headers that were made during the header selection do not exist yet, so they are created first
+func findSelectedSubHeader(mainHeader string, subHeader string, files *sshmkr_templates.ConfigFiles) (*sshmkr_templates.ConfigDocument, int, error) {
+	if len(sshmkr_reader.TrimHeaderIndicator(mainHeader)) <= 0 || len(sshmkr_reader.TrimHeaderIndicator(subHeader)) <= 0 {
+		return nil, -1, sshmkr_templates.NewConfigError(sshmkr_templates.ErrHeaderNotFound, "Main or sub header is empty! Please select a valid header to place the host under!")
+	}
+
+	doc, subHeaderIndex := files.FindSubHeader(mainHeader, subHeader)
+	if subHeaderIndex == -1 {
+		doc, subHeaderIndex = EnsureHeaderSection(sshmkr_reader.TrimHeaderIndicator(mainHeader), sshmkr_reader.TrimHeaderIndicator(subHeader), files)
+	}
+	return doc, subHeaderIndex, nil
+}
+
+// Places the given entries at the end of the section that starts at the given header index
+// Blank lines are added so that the new entries are separated from the ones around them
+func InsertIntoSection(headerIndex int, newEntries []*sshmkr_templates.ConfigEntry, doc *sshmkr_templates.ConfigDocument) {
+	sectionEnd := doc.FindSectionEnd(headerIndex)
+
+	// We place the new entries after the last non blank line of the section
+	insertIndex := sectionEnd
+	for insertIndex - 1 > headerIndex && doc.Entries[insertIndex-1].Kind == sshmkr_templates.BlankLine {
+		insertIndex = insertIndex - 1
+	}
+
+	toInsert := []*sshmkr_templates.ConfigEntry{}
+	if insertIndex - 1 > headerIndex {
+		toInsert = append(toInsert, sshmkr_templates.NewBlankEntry())
+	}
+	toInsert = append(toInsert, newEntries...)
+	if insertIndex == sectionEnd && sectionEnd < len(doc.Entries) {
+		toInsert = append(toInsert, sshmkr_templates.NewBlankEntry())
+	}
+
+	doc.InsertEntries(insertIndex, toInsert...)
+}
+
+// Turns the string that was filled in from a template into entries that can be placed in a document
+func ParseTemplatedConfig(templateString string) []*sshmkr_templates.ConfigEntry {
+	return sshmkr_reader.ParseConfigDocument([]byte(strings.Trim(templateString, "\n"))).Entries
 }
@@ -0,0 +1,61 @@
+package sshmkr_commands
+
+import (
+	"sshmkr/templates"
+)
+
+// Adds an extra pattern to the Host line of an existing host config
+func AddHostAlias(hostname string, alias string, files *sshmkr_templates.ConfigFiles) error {
+	host, err := findAliasHost(hostname, alias, files)
+	if err != nil {
+		return err
+	}
+
+	if _, _, aliasHost := files.FindBlock(alias, false); aliasHost != nil {
+		return sshmkr_templates.NewConfigError(sshmkr_templates.ErrDuplicateHost, "The alias", alias, "is already used by host", aliasHost.GetName(), "!")
+	}
+
+	host.SetPatterns(append(host.GetPatterns(), alias))
+	return nil
+}
+
+// Removes a pattern from the Host line of an existing host config
+// The last pattern of a host cannot be removed, since that would leave a Host line with no name
+func RemoveHostAlias(hostname string, alias string, files *sshmkr_templates.ConfigFiles) error {
+	host, err := findAliasHost(hostname, alias, files)
+	if err != nil {
+		return err
+	}
+
+	if !host.HasPattern(alias) {
+		return sshmkr_templates.NewConfigError(sshmkr_templates.ErrInvalidInput, "Host", hostname, "does not have the alias", alias, "!")
+	}
+
+	newPatterns := []string{}
+	for _, currPattern := range host.GetPatterns() {
+		if currPattern != alias {
+			newPatterns = append(newPatterns, currPattern)
+		}
+	}
+	if len(newPatterns) == 0 {
+		return sshmkr_templates.NewConfigError(sshmkr_templates.ErrInvalidInput, "Cannot remove", alias, "since it is the only name of the host! Use delete to remove the host instead.")
+	}
+
+	host.SetPatterns(newPatterns)
+	return nil
+}
+
+// Helper method that checks the passed in flags and finds the host whose aliases are being changed
+func findAliasHost(hostname string, alias string, files *sshmkr_templates.ConfigFiles) (*sshmkr_templates.ConfigEntry, error) {
+	if len(alias) <= 0 {
+		return nil, sshmkr_templates.NewConfigError(sshmkr_templates.ErrInvalidInput, "Source or alias flag is empty! Please pass in a valid hostname and alias!")
+	}
+
+	_, _, host, err := FindSourceHost(hostname, false, "change the aliases of", files)
+	if err != nil {
+		return nil, err
+	} else if host.Kind != sshmkr_templates.HostLine {
+		return nil, sshmkr_templates.NewConfigError(sshmkr_templates.ErrInvalidInput, "Only Host blocks can have aliases!")
+	}
+	return host, nil
+}
@@ -1,53 +1,28 @@
 package sshmkr_commands
 
 import (
-	"fmt"
-	"os"
-	"strings"
-	"sshmkr/reader"
+	"sshmkr/templates"
 )
 
 // Comments/Uncomments a specific host config depending if it was already commented or not
-// Return the updated file contents and if it did comment it out
-func CommentHostConfig(hostname string, fileContents []byte) (string, bool) {
-	if len(hostname) <= 0 {
-		fmt.Println("Source flag is empty! Please pass in a valid hostname to comment in/out!")
-		os.Exit(-1)
+// Return if it did comment it out
+func CommentHostConfig(hostname string, files *sshmkr_templates.ConfigFiles) (bool, error) {
+	_, _, host, err := FindSourceHost(hostname, true, "comment in/out", files)
+	if err != nil {
+		return false, err
 	}
-	
-	foundHost := false		// Has the method found the matching hostname
-	hasCommented := false	// Did we comment out the config?
 
-	hostSearch := fmt.Sprintf("Host %s", hostname)
-	fileContentLines := strings.Split(string(fileContents), "\n")
-	
-	for currIndex, currLine := range fileContentLines {
-		
-		if foundHost == false {
-			if strings.Contains(currLine, hostSearch) {
-				// We found the specified hostname in the original file
-				foundHost = true
-			} 
-		} 
-		
-		if foundHost == true {
-			if currLine == "" || currIndex + 1 >= len(fileContentLines) {
-				// We reached the end of the modding and break out of the loop
-				break
-			} else if !strings.Contains(currLine, sshmkr_reader.COMMENT_IND) {
-				// Once we find the matching host, we omit/readd it in the config
-				fileContentLines[currIndex] = fmt.Sprintf("%s%s", sshmkr_reader.COMMENT_IND, currLine)
-				hasCommented = true
-			} else {
-				fileContentLines[currIndex] = currLine[len(sshmkr_reader.COMMENT_IND):]
-			}	
-		}		
-	}
+	return ToggleEntryComment(host), nil
+}
 
-	if foundHost == false {
-		fmt.Println("Cannot find host", hostname, "in config. Typo maybe?")
-		os.Exit(-1)
-	}
+// Comments in/out the Host/Match line of a block and all of its options
+// Returns true if the block is now commented out
+func ToggleEntryComment(entry *sshmkr_templates.ConfigEntry) bool {
+	hasCommented := !entry.IsCommented()
 
-	return strings.Join(fileContentLines, "\n"), hasCommented
-}
\ No newline at end of file
+	// Only the options are toggled, the comments in the block stay as they are
+	for _, currLine := range append(entry.GetOptions(), entry.GetHeaderLine()) {
+		currLine.SetCommented(hasCommented)
+	}
+	return hasCommented
+}
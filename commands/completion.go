@@ -0,0 +1,424 @@
+package sshmkr_commands
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sshmkr/reader"
+	"sshmkr/templates"
+)
+
+// Constants
+const FILE_COMPLETION_KIND = "files"	// Values that are completed with the file names of the shell instead of calling back into sshmkr
+
+// The values that are always the same, along with the kinds that are read from the config
+var staticCompletionValues = map[string][]string{
+	"formats": {"text", "json", "yaml"},
+	"modes": {"substring", "glob", "regex", "fuzzy"},
+	"shells": {"bash", "zsh", "fish"},
+}
+
+// The verbs that come right after the subcommands that have them
+var completionSubCommands = map[string][]string{
+	"alias": {"add", "remove"},
+	"header": {"add", "rename", "delete", "move"},
+	"template": {"list", "show", "add", "edit", "delete", "from-host"},
+}
+
+// The kind of value that is passed in as the argument after the subcommand
+var completionArgKinds = map[string]string{
+	"move": "hosts",
+	"resolve": "hosts",
+	"completion": "shells",
+}
+
+// Prints out a completion script for the given shell, which completes the subcommands, their flags and the values of those flags
+// Host names, template names and header paths are completed by calling back into sshmkr with the hidden __complete command
+func GenerateCompletionScript(shell string, cmds []*flag.FlagSet) error {
+	cmdNames := []string{}
+	for _, currCmd := range cmds {
+		cmdNames = append(cmdNames, currCmd.Name())
+	}
+
+	switch shell {
+		case "bash":
+			fmt.Print(generateBashCompletion(cmdNames, cmds))
+		case "zsh":
+			fmt.Print(generateZshCompletion(cmdNames, cmds))
+		case "fish":
+			fmt.Print(generateFishCompletion(cmdNames, cmds))
+		default:
+			return sshmkr_templates.NewConfigError(sshmkr_templates.ErrInvalidInput, "Shell", shell, "is not supported! Available shells are: [bash, zsh, fish]")
+	}
+	return nil
+}
+
+// Prints out every value of the given kind, one per line, for the completion scripts to use
+// Nothing is printed if the config cannot be read, so a missing config never shows up as a completion
+func PrintCompletionValues(kind string, configLoc string) {
+	if values, isStatic := staticCompletionValues[kind]; isStatic {
+		fmt.Println(strings.Join(values, "\n"))
+		return
+	}
+
+	readLoc := configLoc
+	if kind == "templates" {
+		readLoc = sshmkr_reader.GetTemplatesLoc(configLoc)
+	}
+	if _, err := os.Stat(readLoc); err != nil {
+		return
+	}
+	files, err := sshmkr_reader.ReadConfigFiles(readLoc)
+	if err != nil {
+		return
+	}
+
+	switch kind {
+		case "hosts", "templates":
+			for _, currName := range getHostPatterns(files) {
+				fmt.Println(currName)
+			}
+		case "headers":
+			// The same header can show up in more than one file, but it only needs to be completed once
+			seenPaths := map[string]bool{}
+			for _, currHeader := range sshmkr_reader.ParseConfigHeaders(files) {
+				mainName := sshmkr_reader.TrimHeaderIndicator(currHeader.GetMainHeader())
+				headerPaths := []string{mainName}
+				for _, currSubHeader := range currHeader.GetSubHeaders() {
+					headerPaths = append(headerPaths, mainName + "/" + sshmkr_reader.TrimHeaderIndicator(currSubHeader))
+				}
+				for _, currPath := range headerPaths {
+					if !seenPaths[currPath] {
+						seenPaths[currPath] = true
+						fmt.Println(currPath)
+					}
+				}
+			}
+	}
+}
+
+// Helper method that gets every pattern that a Host block can be referred to by, commented out ones included
+func getHostPatterns(files *sshmkr_templates.ConfigFiles) []string {
+	seenPatterns := map[string]bool{}
+	patterns := []string{}
+	files.WalkEntries(func(doc *sshmkr_templates.ConfigDocument, index int) bool {
+		currEntry := doc.Entries[index]
+		if currEntry.Kind != sshmkr_templates.HostLine {
+			return true
+		}
+		for _, currPattern := range currEntry.GetPatterns() {
+			if !strings.HasPrefix(currPattern, "!") && !seenPatterns[currPattern] {
+				seenPatterns[currPattern] = true
+				patterns = append(patterns, currPattern)
+			}
+		}
+		return true
+	})
+	return patterns
+}
+
+// Helper method that gets the kind of value that a flag of a subcommand is completed with, or "" if it is not completed
+func getFlagValueKind(cmdName string, flagName string) string {
+	switch flagName {
+		case "source":
+			if cmdName == "add" || cmdName == "template" {
+				return "templates"
+			}
+			return "hosts"
+		case "header", "to", "before", "after":
+			return "headers"
+		case "output":
+			return "formats"
+		case "mode":
+			return "modes"
+		case "answers":
+			return FILE_COMPLETION_KIND
+	}
+	return ""
+}
+
+// Helper method that gets the flags of a subcommand, along with if each of them takes a value
+func getCompletionFlags(cmd *flag.FlagSet) ([]string, map[string]bool) {
+	flagNames := []string{}
+	takesValue := map[string]bool{}
+	cmd.VisitAll(func(currFlag *flag.Flag) {
+		flagNames = append(flagNames, currFlag.Name)
+		boolFlag, isBoolFlag := currFlag.Value.(interface{ IsBoolFlag() bool })
+		takesValue[currFlag.Name] = !isBoolFlag || !boolFlag.IsBoolFlag()
+	})
+	sort.Strings(flagNames)
+	return flagNames, takesValue
+}
+
+// Helper method that puts a dash in front of every flag name
+func getDashedFlags(flagNames []string) string {
+	dashedFlags := []string{}
+	for _, currName := range flagNames {
+		dashedFlags = append(dashedFlags, "-" + currName)
+	}
+	return strings.Join(dashedFlags, " ")
+}
+
+// Helper method that builds the bash completion script
+func generateBashCompletion(cmdNames []string, cmds []*flag.FlagSet) string {
+	valueCases, flagCases, argCases := "", "", ""
+	for _, currCmd := range cmds {
+		flagNames, _ := getCompletionFlags(currCmd)
+		flagCases = flagCases + fmt.Sprintf("\t\t\t%s) flags=\"%s\" ;;\n", currCmd.Name(), getDashedFlags(flagNames))
+		for _, currFlag := range flagNames {
+			if kind := getFlagValueKind(currCmd.Name(), currFlag); kind == FILE_COMPLETION_KIND {
+				valueCases = valueCases + fmt.Sprintf("\t\t\t%s:%s) COMPREPLY=($(compgen -f -- \"$cur\")); return ;;\n", currCmd.Name(), currFlag)
+			} else if kind != "" {
+				valueCases = valueCases + fmt.Sprintf("\t\t\t%s:%s) _sshmkr_complete_values %s; return ;;\n", currCmd.Name(), currFlag, kind)
+			}
+		}
+
+		if subCommands, hasSubCommands := completionSubCommands[currCmd.Name()]; hasSubCommands {
+			argCases = argCases + fmt.Sprintf("\t\t\t%s) COMPREPLY=($(compgen -W \"%s\" -- \"$cur\")) ;;\n", currCmd.Name(), strings.Join(subCommands, " "))
+		} else if kind, hasArg := completionArgKinds[currCmd.Name()]; hasArg {
+			argCases = argCases + fmt.Sprintf("\t\t\t%s) _sshmkr_complete_values %s ;;\n", currCmd.Name(), kind)
+		}
+	}
+
+	return fmt.Sprintf(`# bash completion for sshmkr
+# Load it with: source <(sshmkr completion bash)
+
+_sshmkr_complete_values() {
+	local value
+	while IFS= read -r value; do
+		if [[ "$value" == "$cur"* ]]; then
+			COMPREPLY+=("$(printf '%%q' "$value")")
+		fi
+	done < <(command sshmkr "${_sshmkr_path_args[@]}" __complete "$1" 2>/dev/null)
+}
+
+_sshmkr() {
+	local cur="${COMP_WORDS[COMP_CWORD]}" prev="${COMP_WORDS[COMP_CWORD-1]}"
+	local cmd="" cmd_index=0 flags="" i
+	_sshmkr_path_args=()
+	COMPREPLY=()
+
+	# The global flags come before the subcommand, so the first word that is not a flag is the subcommand
+	for ((i = 1; i < COMP_CWORD; i++)); do
+		case "${COMP_WORDS[i]}" in
+			-path|--path|-p|--p)
+				_sshmkr_path_args=(-path "${COMP_WORDS[i+1]}")
+				i=$((i + 1)) ;;
+			-backups|--backups)
+				i=$((i + 1)) ;;
+			-*) ;;
+			*)
+				cmd="${COMP_WORDS[i]}"
+				cmd_index=$i
+				break ;;
+		esac
+	done
+
+	case "$prev" in
+		-path|--path|-p|--p)
+			COMPREPLY=($(compgen -f -- "$cur"))
+			return ;;
+	esac
+
+	if [[ -z "$cmd" ]]; then
+		if [[ "$cur" == -* ]]; then
+			COMPREPLY=($(compgen -W "-help -version -path -backups -dry-run -confirm" -- "$cur"))
+		else
+			COMPREPLY=($(compgen -W "%s" -- "$cur"))
+		fi
+		return
+	fi
+
+	# Only the flags that take a value have a case, so a flag without one falls through to the next word
+	local flag="${prev#-}"
+	flag="${flag#-}"
+	if [[ "$prev" == -* ]]; then
+		case "$cmd:$flag" in
+%s		esac
+	fi
+
+	if [[ "$cur" == -* ]]; then
+		case "$cmd" in
+%s		esac
+		COMPREPLY=($(compgen -W "$flags" -- "$cur"))
+	elif [[ $COMP_CWORD -eq $((cmd_index + 1)) ]]; then
+		case "$cmd" in
+%s		esac
+	fi
+}
+
+complete -F _sshmkr sshmkr
+`, strings.Join(cmdNames, " "), valueCases, flagCases, argCases)
+}
+
+// Helper method that builds the zsh completion script
+func generateZshCompletion(cmdNames []string, cmds []*flag.FlagSet) string {
+	valueCases, flagCases, argCases := "", "", ""
+	for _, currCmd := range cmds {
+		flagNames, _ := getCompletionFlags(currCmd)
+		flagCases = flagCases + fmt.Sprintf("\t\t\t%s) flags=(%s) ;;\n", currCmd.Name(), getDashedFlags(flagNames))
+		for _, currFlag := range flagNames {
+			if kind := getFlagValueKind(currCmd.Name(), currFlag); kind == FILE_COMPLETION_KIND {
+				valueCases = valueCases + fmt.Sprintf("\t\t\t%s:%s) _files; return ;;\n", currCmd.Name(), currFlag)
+			} else if kind != "" {
+				valueCases = valueCases + fmt.Sprintf("\t\t\t%s:%s) _sshmkr_complete_values %s; return ;;\n", currCmd.Name(), currFlag, kind)
+			}
+		}
+
+		if subCommands, hasSubCommands := completionSubCommands[currCmd.Name()]; hasSubCommands {
+			argCases = argCases + fmt.Sprintf("\t\t\t%s) compadd -- %s ;;\n", currCmd.Name(), strings.Join(subCommands, " "))
+		} else if kind, hasArg := completionArgKinds[currCmd.Name()]; hasArg {
+			argCases = argCases + fmt.Sprintf("\t\t\t%s) _sshmkr_complete_values %s ;;\n", currCmd.Name(), kind)
+		}
+	}
+
+	return fmt.Sprintf(`#compdef sshmkr
+# zsh completion for sshmkr
+# Load it with: source <(sshmkr completion zsh)
+
+_sshmkr_complete_values() {
+	local -a values
+	values=(${(f)"$(command sshmkr "${path_args[@]}" __complete "$1" 2>/dev/null)"})
+	compadd -a values
+}
+
+_sshmkr() {
+	local -a path_args flags
+	local cmd="" cmd_index=0 i
+	local prev="${words[CURRENT-1]}"
+	local flag="${${prev#-}#-}"
+
+	# The global flags come before the subcommand, so the first word that is not a flag is the subcommand
+	for ((i = 2; i < CURRENT; i++)); do
+		case "${words[i]}" in
+			-path|--path|-p|--p)
+				path_args=(-path "${words[i+1]}")
+				i=$((i + 1)) ;;
+			-backups|--backups)
+				i=$((i + 1)) ;;
+			-*) ;;
+			*)
+				cmd="${words[i]}"
+				cmd_index=$i
+				break ;;
+		esac
+	done
+
+	case "$prev" in
+		-path|--path|-p|--p)
+			_files
+			return ;;
+	esac
+
+	if [[ -z "$cmd" ]]; then
+		if [[ "$PREFIX" == -* ]]; then
+			compadd -- -help -version -path -backups -dry-run -confirm
+		else
+			compadd -- %s
+		fi
+		return
+	fi
+
+	# Only the flags that take a value have a case, so a flag without one falls through to the next word
+	if [[ "$prev" == -* ]]; then
+		case "$cmd:$flag" in
+%s		esac
+	fi
+
+	if [[ "$PREFIX" == -* ]]; then
+		case "$cmd" in
+%s		esac
+		compadd -a flags
+	elif (( CURRENT == cmd_index + 1 )); then
+		case "$cmd" in
+%s		esac
+	fi
+}
+
+compdef _sshmkr sshmkr
+`, strings.Join(cmdNames, " "), valueCases, flagCases, argCases)
+}
+
+// Helper method that builds the fish completion script
+func generateFishCompletion(cmdNames []string, cmds []*flag.FlagSet) string {
+	completions := ""
+	for _, currCmd := range cmds {
+		flagNames, takesValue := getCompletionFlags(currCmd)
+		for _, currFlag := range flagNames {
+			completion := fmt.Sprintf("complete -c sshmkr -n '__sshmkr_using %s' -o %s", currCmd.Name(), currFlag)
+			if kind := getFlagValueKind(currCmd.Name(), currFlag); kind == FILE_COMPLETION_KIND {
+				completion = completion + " -r -F"
+			} else if kind != "" {
+				completion = completion + fmt.Sprintf(" -x -a '(__sshmkr_values %s)'", kind)
+			} else if takesValue[currFlag] {
+				completion = completion + " -x"
+			}
+			completions = completions + fmt.Sprintf("%s -d '%s'\n", completion, strings.Replace(currCmd.Lookup(currFlag).Usage, "'", "\\'", -1))
+		}
+
+		if subCommands, hasSubCommands := completionSubCommands[currCmd.Name()]; hasSubCommands {
+			completions = completions + fmt.Sprintf("complete -c sshmkr -n '__sshmkr_at_first_arg %s' -a '%s'\n", currCmd.Name(), strings.Join(subCommands, " "))
+		} else if kind, hasArg := completionArgKinds[currCmd.Name()]; hasArg {
+			completions = completions + fmt.Sprintf("complete -c sshmkr -n '__sshmkr_at_first_arg %s' -a '(__sshmkr_values %s)'\n", currCmd.Name(), kind)
+		}
+	}
+
+	return fmt.Sprintf(`# fish completion for sshmkr
+# Load it with: sshmkr completion fish | source
+
+# The global flags come before the subcommand, so the first word that is not a flag is the subcommand
+function __sshmkr_subcommand
+	set -l tokens (commandline -opc)
+	set -l i 2
+	while test $i -le (count $tokens)
+		switch $tokens[$i]
+			case -path --path -p --p -backups --backups
+				set i (math $i + 1)
+			case '-*'
+			case '*'
+				echo $tokens[$i]
+				return
+		end
+		set i (math $i + 1)
+	end
+end
+
+function __sshmkr_path_args
+	set -l tokens (commandline -opc)
+	set -l i 2
+	while test $i -lt (count $tokens)
+		if contains -- $tokens[$i] -path --path -p --p
+			echo -- -path
+			echo -- $tokens[(math $i + 1)]
+			return
+		end
+		set i (math $i + 1)
+	end
+end
+
+function __sshmkr_values
+	command sshmkr (__sshmkr_path_args) __complete $argv[1] 2>/dev/null
+end
+
+function __sshmkr_using
+	set -l cmd (__sshmkr_subcommand)
+	test "$cmd" = "$argv[1]"
+end
+
+function __sshmkr_at_first_arg
+	set -l tokens (commandline -opc)
+	__sshmkr_using $argv[1]; and test "$tokens[-1]" = "$argv[1]"
+end
+
+complete -c sshmkr -f
+complete -c sshmkr -n "__sshmkr_using ''" -a '%s'
+complete -c sshmkr -n "__sshmkr_using ''" -o help -d 'Displays the help page for a specific command (or generally)'
+complete -c sshmkr -n "__sshmkr_using ''" -o version -d 'Prints out the current version of the application'
+complete -c sshmkr -n "__sshmkr_using ''" -o path -r -F -d 'Changes the default path to look for the ssh_config'
+complete -c sshmkr -n "__sshmkr_using ''" -o backups -x -d 'Number of backups of the ssh_config to keep when it is changed'
+complete -c sshmkr -n "__sshmkr_using ''" -o dry-run -d 'Prints out a diff of the changes instead of writing them'
+complete -c sshmkr -n "__sshmkr_using ''" -o confirm -d 'Prints out a diff of the changes and asks before writing them'
+%s`, strings.Join(cmdNames, " "), completions)
+}
@@ -1,60 +1,33 @@
 package sshmkr_commands
 
 import (
-	"strings"
-	"fmt"
-	"os"
+	"sshmkr/templates"
 )
 
-// Removes a specified host config from the ssh_config value
-// And returns the updated file content
-func RemoveHostConfig(hostname string, fileContents []byte) string {
-	if len(hostname) <= 0 {
-		fmt.Println("Source flag is empty! Please pass in a valid hostname to remove!")
-		os.Exit(-1)
+// Removes a specified host config from the file it lives in
+// The comments that are attached to the host are removed with it
+func RemoveHostConfig(hostname string, files *sshmkr_templates.ConfigFiles) error {
+	doc, hostIndex, _, err := FindSourceHost(hostname, false, "remove", files)
+	if err != nil {
+		return err
 	}
-	
-	foundHost := false		// Has the method found the matching hostname
-	finishDelete := false	// Has the method finished parsing out the specified hostname's config
-	newFileIndex := 0		// The current array index that the new byte array is currently on
-	numbLinesRemoved := 0	// How many lines will be omitted from the new file array
 
-	hostSearch := fmt.Sprintf("Host %s", hostname)
+	RemoveEntryWithSpacing(hostIndex, doc)
+	return nil
+}
 
-	// In order to effectively delete a specific spot in the file
-	// We take the original file contents and make a copy of it to another array
-	// without copying over the specified config
-	fileContentLines := strings.Split(string(fileContents), "\n")
-	newFileContents := make([]string, len(fileContentLines))
-	
-	for currIndex, currLine := range fileContentLines {
-		
-		if currLine == hostSearch {
-			// We found the specified hostname in the original file
-			foundHost = true
-		} 
-
-		if foundHost == true && finishDelete == false {
-			// Once we find the matching host, we omit it from the copy
-			numbLinesRemoved = numbLinesRemoved + 1
-			if currLine == "" || currIndex + 1 >= len(fileContentLines) {
-				// We reached the end of the config we wanted to remove and resume copying
-				finishDelete = true
-			}
-		} else {
-			// We copy the original file to the new file array
-			newFileContents[newFileIndex] = currLine
-			newFileIndex = newFileIndex + 1
-		}
-	}
-
-	if foundHost == false {
-		fmt.Println("Cannot find host", hostname, "in config. Typo maybe?")
-		os.Exit(-1)
+// Removes the entry at the given index, along with the blank line that separated it from the next entry
+// If a header comes right after that blank line, the blank line before the entry is removed instead, so the header stays separated
+func RemoveEntryWithSpacing(index int, doc *sshmkr_templates.ConfigDocument) {
+	doc.RemoveEntry(index)
+	headerIsNext := index + 1 < len(doc.Entries) &&
+		(doc.Entries[index+1].Kind == sshmkr_templates.MainHeaderLine || doc.Entries[index+1].Kind == sshmkr_templates.SubHeaderLine)
+	blankIsBefore := index > 0 && doc.Entries[index-1].Kind == sshmkr_templates.BlankLine
+	// The last blank line of the file is the newline at its end, so it is only removed if a blank line before it takes its place
+	blankIsFileEnd := index == len(doc.Entries) - 1
+	if index < len(doc.Entries) && doc.Entries[index].Kind == sshmkr_templates.BlankLine && !headerIsNext && (!blankIsFileEnd || blankIsBefore) {
+		doc.RemoveEntry(index)
+	} else if blankIsBefore {
+		doc.RemoveEntry(index - 1)
 	}
-
-	// We take a slice of the new array (since we are removing X lines from the file)
-	// and return it
-	newFileArray := strings.Join(newFileContents, "\n")
-	return newFileArray[:len(newFileArray) - numbLinesRemoved]
-}
\ No newline at end of file
+}
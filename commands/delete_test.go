@@ -0,0 +1,60 @@
+package sshmkr_commands
+
+import (
+	"testing"
+	"sshmkr/reader"
+	"sshmkr/templates"
+)
+
+func TestRemoveHostConfigSpacing(t *testing.T) {
+	testCases := []struct {
+		name string
+		hostname string
+		contents string
+		want string
+	}{
+		{
+			"host in the middle",
+			"web",
+			"#### Work\n## Servers\nHost web\n\tUser me\n\nHost db\n\tUser me\n",
+			"#### Work\n## Servers\nHost db\n\tUser me\n",
+		},
+		{
+			"last host of the file",
+			"db",
+			"#### Work\n## Servers\nHost web\n\tUser me\n\nHost db\n\tUser me\n",
+			"#### Work\n## Servers\nHost web\n\tUser me\n",
+		},
+		{
+			"last host under a header",
+			"bastion",
+			"#### Project 1\n## Servers\nHost web\n\tUser me\n\n## Bastions\nHost jump\n\tUser me\n\nHost bastion\n\tUser me\n\n#### Project 2\n## Servers\nHost db\n\tUser me\n",
+			"#### Project 1\n## Servers\nHost web\n\tUser me\n\n## Bastions\nHost jump\n\tUser me\n\n#### Project 2\n## Servers\nHost db\n\tUser me\n",
+		},
+		{
+			"only host at the end of the file",
+			"db",
+			"#### Work\n## Servers\nHost db\n\tUser me\n",
+			"#### Work\n## Servers\n",
+		},
+		{
+			"only host under a header",
+			"bastion",
+			"#### Project 1\n## Bastions\nHost bastion\n\tUser me\n\n#### Project 2\n## Servers\nHost db\n\tUser me\n",
+			"#### Project 1\n## Bastions\n\n#### Project 2\n## Servers\nHost db\n\tUser me\n",
+		},
+	}
+
+	for _, currCase := range testCases {
+		t.Run(currCase.name, func(t *testing.T) {
+			doc := sshmkr_reader.ParseConfigDocument([]byte(currCase.contents))
+			files := &sshmkr_templates.ConfigFiles{Docs: []*sshmkr_templates.ConfigDocument{doc}}
+			if err := RemoveHostConfig(currCase.hostname, files); err != nil {
+				t.Fatal(err)
+			}
+			if got := doc.String(); got != currCase.want {
+				t.Errorf("RemoveHostConfig() left\n%q\nwant:\n%q", got, currCase.want)
+			}
+		})
+	}
+}
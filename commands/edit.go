@@ -2,38 +2,50 @@ package sshmkr_commands
 
 import (
 	"strings"
+	"sshmkr/templates"
 )
 
-func EditExisingConfig(origHostName string, templateString string, fileContents []byte) string {
+// Edits an existing host config with the values that were filled in from its template
+// The indentation and comments of the original host config are kept
+func EditExisingConfig(origHostName string, templateString string, files *sshmkr_templates.ConfigFiles) error {
 	/*
 	*	The logic on this script goes by the following:
 	*	1. Search for the hostname that we want to edit.
-	*	2. Once we find it, we do an inline replacement in the array
-	*	3. We exit the script.
+	*	2. Once we find it, we do an inline replacement of each value in the block
+	*	3. Any key that the block did not have is added to the end of it
 	*/
 
-	foundHost := false
-	fileContentsArray := strings.Split(string(fileContents), "\n")
-	templateArray := strings.Split(templateString, "\n")
-	templateArrayLength := len(templateArray)
-	templateArrayIndex := 1	// This is 1 because the first value in this split array is an empty line
+	_, _, host, err := FindSourceHost(origHostName, false, "edit", files)
+	if err != nil {
+		return err
+	}
+
+	editedEntries := ParseTemplatedConfig(templateString)
+	if len(editedEntries) == 0 || !editedEntries[0].IsBlock() {
+		return nil
+	}
+	editedHost := editedEntries[0]
+	if err := checkNamesNotUsed(editedHost, host, files); err != nil {
+		return err
+	}
+	host.GetHeaderLine().SetValue(editedHost.GetHeaderLine().Value)
 
-	for currIndex, currLine := range fileContentsArray {
-		if strings.Contains(currLine, origHostName) {
-			foundHost = true
-		} 
-		if foundHost == true {
-			if templateArrayIndex < templateArrayLength {
-				// Once we find the host name, we perform in line edits to the config
-				fileContentsArray[currIndex] = templateArray[templateArrayIndex]
-				templateArrayIndex = templateArrayIndex + 1
-			} else {
-				// We reached the end of the host config so we just exit the loop
-				break;
+	// Keys can be repeated (i.e IdentityFile), so we match each option to the next unused one with the same key
+	usedOptions := map[*sshmkr_templates.ConfigLine]bool{}
+	for _, editedOption := range editedHost.GetOptions() {
+		foundOption := false
+		for _, currOption := range host.GetOptions() {
+			if !usedOptions[currOption] && strings.EqualFold(currOption.Key, editedOption.Key) {
+				currOption.SetValue(editedOption.Value)
+				usedOptions[currOption] = true
+				foundOption = true
+				break
 			}
 		}
-	}
 
-	newContents := strings.Join(fileContentsArray, "\n")
-	return newContents
-}
\ No newline at end of file
+		if !foundOption {
+			host.Lines = append(host.Lines, editedOption)
+		}
+	}
+	return nil
+}
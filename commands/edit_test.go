@@ -0,0 +1,43 @@
+package sshmkr_commands
+
+import (
+	"errors"
+	"io/ioutil"
+	"strings"
+	"testing"
+	"sshmkr/input"
+	"sshmkr/reader"
+	"sshmkr/templates"
+	"github.com/kevinburke/ssh_config"
+)
+
+func TestEditHostWithAtComment(t *testing.T) {
+	contents := "#### Work\n## Servers\nHost web\n\tUser me\n\t# @alice asked for this port\n\tPort 22\n"
+	want := "#### Work\n## Servers\nHost web\n\tUser me\n\t# @alice asked for this port\n\tPort 2222\n"
+
+	doc := sshmkr_reader.ParseConfigDocument([]byte(contents))
+	files := &sshmkr_templates.ConfigFiles{Docs: []*sshmkr_templates.ConfigDocument{doc}}
+
+	// The comments of a host in the ssh_config are notes, not annotations
+	template, err := sshmkr_reader.ReadSpecificTemplate("web", files)
+	if err != nil {
+		t.Fatalf("ReadSpecificTemplate() error = %v", err)
+	}
+	sshmkr_input.Input = strings.NewReader("")
+	sshmkr_input.Output = ioutil.Discard
+	editedConfig, _, err := sshmkr_input.InterpolateUserInput(template, sshmkr_templates.InputOptions{SetValues: []ssh_config.KV{{Key: "Port", Value: "2222"}}, NonInteractive: true})
+	if err != nil {
+		t.Fatalf("InterpolateUserInput() error = %v", err)
+	}
+	if err = EditExisingConfig("web", editedConfig, files); err != nil {
+		t.Fatalf("EditExisingConfig() error = %v", err)
+	}
+	if got := doc.String(); got != want {
+		t.Errorf("EditExisingConfig() left\n%q\nwant:\n%q", got, want)
+	}
+
+	// The same comment in the templates file is still an unknown annotation
+	if _, err = sshmkr_reader.ReadAnnotatedTemplate("web", files); !errors.Is(err, sshmkr_templates.ErrInvalidInput) {
+		t.Errorf("ReadAnnotatedTemplate() error = %v, want %v", err, sshmkr_templates.ErrInvalidInput)
+	}
+}
@@ -0,0 +1,153 @@
+package sshmkr_commands
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"sshmkr/reader"
+	"sshmkr/templates"
+)
+
+// The option keys that are searched, along with the host names and comments
+var searchedKeys = []string{"Hostname", "User", "IdentityFile", "ProxyJump"}
+
+// Prints out every host that has a name, searched option or comment that matches the query
+// The mode decides how the query is matched: substring, glob (* and ?), regex or fuzzy (the letters of the query in order)
+// Each host is printed with the header it lives under, followed by the values that matched
+// Returns the number of hosts that were found
+func FindHosts(query string, mode string, includeCommented bool, files *sshmkr_templates.ConfigFiles) (int, error) {
+	if len(query) <= 0 {
+		return 0, sshmkr_templates.NewConfigError(sshmkr_templates.ErrInvalidInput, "Query is empty! Please pass in something to search for!")
+	}
+	matches, err := getQueryMatcher(query, mode)
+	if err != nil {
+		return 0, err
+	}
+
+	foundCount := 0
+	files.WalkEntries(func(doc *sshmkr_templates.ConfigDocument, index int) bool {
+		currEntry := doc.Entries[index]
+		if !currEntry.IsBlock() || (currEntry.IsCommented() && !includeCommented) {
+			return true
+		}
+
+		matchedLines := []string{}
+		headerLine := currEntry.GetHeaderLine()
+		for _, currName := range append(currEntry.GetPatterns(), currEntry.GetName()) {
+			if matches(currName) {
+				matchedLines = append(matchedLines, headerLine.Key + " " + headerLine.Value)
+				break
+			}
+		}
+
+		for _, currOption := range currEntry.GetOptions() {
+			if isSearchedKey(currOption.Key) && matches(currOption.Value) {
+				matchedLines = append(matchedLines, currOption.Key + " " + currOption.Value)
+			}
+			if comment := getCommentText(currOption.Comment); comment != "" && matches(comment) {
+				matchedLines = append(matchedLines, "# " + comment)
+			}
+		}
+		for _, currComment := range currEntry.Comments {
+			if comment := getCommentText(currComment.Raw); comment != "" && matches(comment) {
+				matchedLines = append(matchedLines, "# " + comment)
+			}
+		}
+
+		if len(matchedLines) > 0 {
+			foundCount = foundCount + 1
+			fmt.Printf("%s  (%s)\n", formatFoundName(currEntry), describeHeaderPath(doc, index, files))
+			for _, currLine := range matchedLines {
+				fmt.Printf("\t%s\n", currLine)
+			}
+		}
+		return true
+	})
+
+	if foundCount == 0 {
+		fmt.Println("No hosts found that match", query, "!")
+	}
+	return foundCount, nil
+}
+
+// Helper method that turns the query into a function that checks if a value matches it
+// Every mode other than regex ignores casing
+func getQueryMatcher(query string, mode string) (func(string) bool, error) {
+	lowerQuery := strings.ToLower(query)
+
+	switch mode {
+		case "", "substring":
+			return func(value string) bool {
+				return strings.Contains(strings.ToLower(value), lowerQuery)
+			}, nil
+		case "glob":
+			return func(value string) bool {
+				return MatchesPattern(value, query)
+			}, nil
+		case "regex":
+			queryRegex, err := regexp.Compile(query)
+			if err != nil {
+				return nil, sshmkr_templates.NewConfigError(sshmkr_templates.ErrInvalidInput, "Query", query, "is not a valid regex!", err)
+			}
+			return queryRegex.MatchString, nil
+		case "fuzzy":
+			return func(value string) bool {
+				return isFuzzyMatch(strings.ToLower(value), lowerQuery)
+			}, nil
+	}
+
+	return nil, sshmkr_templates.NewConfigError(sshmkr_templates.ErrInvalidInput, "Search mode", mode, "is not supported! Available modes are: [substring, glob, regex, fuzzy]")
+}
+
+// Helper method that checks if every character of the query shows up in the value, in the same order
+func isFuzzyMatch(value string, query string) bool {
+	queryRunes := []rune(query)
+	queryIndex := 0
+	for _, currRune := range value {
+		if queryIndex < len(queryRunes) && currRune == queryRunes[queryIndex] {
+			queryIndex = queryIndex + 1
+		}
+	}
+	return queryIndex == len(queryRunes)
+}
+
+// Helper method that checks if an option key is one of the keys that are searched
+func isSearchedKey(key string) bool {
+	for _, currKey := range searchedKeys {
+		if strings.EqualFold(currKey, key) {
+			return true
+		}
+	}
+	return false
+}
+
+// Helper method that gets the text of a comment, without the # in front of it
+func getCommentText(comment string) string {
+	return strings.TrimSpace(strings.TrimLeft(strings.TrimSpace(comment), sshmkr_reader.COMMENT_IND))
+}
+
+// Helper method that formats the name of a found host, marking it if it was commented out
+func formatFoundName(entry *sshmkr_templates.ConfigEntry) string {
+	if entry.IsCommented() {
+		return entry.GetName() + " (commented)"
+	}
+	return entry.GetName()
+}
+
+// Helper method that describes the main/sub header that an entry lives under as a "Main Header/Sub Header" path
+// Entries in included files are also labeled with the file they are in
+func describeHeaderPath(doc *sshmkr_templates.ConfigDocument, index int, files *sshmkr_templates.ConfigFiles) string {
+	mainHeader, subHeader := doc.GetHeadersOf(index)
+
+	headerPath := "no header"
+	if mainHeader != "" {
+		headerPath = sshmkr_reader.TrimHeaderIndicator(mainHeader)
+		if subHeader != "" {
+			headerPath = headerPath + "/" + sshmkr_reader.TrimHeaderIndicator(subHeader)
+		}
+	}
+	if doc != files.GetMainDoc() {
+		headerPath = fmt.Sprintf("%s in %s", headerPath, doc.Path)
+	}
+	return headerPath
+}
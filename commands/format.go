@@ -0,0 +1,111 @@
+package sshmkr_commands
+
+import (
+	"strings"
+	"sshmkr/reader"
+	"sshmkr/templates"
+)
+
+// Constants
+const OPTION_INDENT = "\t"
+
+// Rewrites every file of the config into the same layout that sshmkr writes new hosts in
+// Options are indented with a tab, keywords use the casing from the ssh_config man page and
+// there is a single space between each keyword and its value
+// Blocks and headers are separated by a single blank line, and every file ends with a newline
+func FormatConfig(files *sshmkr_templates.ConfigFiles) {
+	for _, currDoc := range files.Docs {
+		for _, currEntry := range currDoc.Entries {
+			formatEntry(currEntry)
+		}
+		formatSpacing(currDoc)
+	}
+}
+
+// Helper method that formats every line of a single entry
+func formatEntry(entry *sshmkr_templates.ConfigEntry) {
+	for _, currComment := range entry.Comments {
+		currComment.Raw = strings.TrimRight(currComment.Raw, " \t")
+	}
+
+	switch entry.Kind {
+		case sshmkr_templates.MainHeaderLine:
+			entry.Lines[0].Raw = sshmkr_reader.MAIN_HEADER_IND + " " + sshmkr_reader.TrimHeaderIndicator(entry.Lines[0].Raw)
+			return
+		case sshmkr_templates.SubHeaderLine:
+			entry.Lines[0].Raw = sshmkr_reader.SUB_HEADER_IND + " " + sshmkr_reader.TrimHeaderIndicator(entry.Lines[0].Raw)
+			return
+		case sshmkr_templates.HostLine, sshmkr_templates.MatchLine:
+			headerLine := entry.GetHeaderLine()
+			headerLine.Value = strings.Join(strings.Fields(headerLine.Value), " ")
+			headerLine.Reformat("", sshmkr_reader.GetCanonicalKeyword(headerLine.Key))
+
+			// Blank lines in the middle of a block are dropped, and only the options that share the block's commented state are moved
+			blockLines := []*sshmkr_templates.ConfigLine{headerLine}
+			for _, currLine := range entry.Lines[1:] {
+				if currLine.Kind == sshmkr_templates.BlankLine {
+					continue
+				} else if currLine.Kind == sshmkr_templates.OptionLine && currLine.Commented == entry.IsCommented() {
+					currLine.Reformat(OPTION_INDENT, sshmkr_reader.GetCanonicalKeyword(currLine.Key))
+				} else {
+					currLine.Raw = strings.TrimRight(currLine.Raw, " \t")
+				}
+				blockLines = append(blockLines, currLine)
+			}
+			entry.Lines = blockLines
+		case sshmkr_templates.OptionLine:
+			// Options outside of a block are not indented, since they do not belong to any host
+			if !entry.Lines[0].Commented {
+				entry.Lines[0].Reformat("", sshmkr_reader.GetCanonicalKeyword(entry.Lines[0].Key))
+			}
+		default:
+			entry.Lines[0].Raw = strings.TrimRight(entry.Lines[0].Raw, " \t")
+	}
+}
+
+// Helper method that places a single blank line between blocks and around headers
+// Blank lines in other places are kept (but never more than one in a row), since they decide which comments belong to a block
+func formatSpacing(doc *sshmkr_templates.ConfigDocument) {
+	spacedEntries := []*sshmkr_templates.ConfigEntry{}
+	var prevEntry *sshmkr_templates.ConfigEntry
+	pendingBlank := false
+
+	for _, currEntry := range doc.Entries {
+		if currEntry.Kind == sshmkr_templates.BlankLine {
+			pendingBlank = prevEntry != nil
+			continue
+		}
+
+		if prevEntry != nil && needsBlankLine(prevEntry, currEntry, pendingBlank) {
+			spacedEntries = append(spacedEntries, sshmkr_templates.NewBlankEntry())
+		}
+		spacedEntries = append(spacedEntries, currEntry)
+		prevEntry = currEntry
+		pendingBlank = false
+	}
+
+	// Every file ends with a single newline
+	if len(spacedEntries) > 0 {
+		spacedEntries = append(spacedEntries, sshmkr_templates.NewBlankEntry())
+	}
+	doc.Entries = spacedEntries
+}
+
+// Helper method that checks if a blank line has to be placed between two entries
+// hadBlankLine is whether there was a blank line between them before they were formatted
+func needsBlankLine(prevEntry *sshmkr_templates.ConfigEntry, currEntry *sshmkr_templates.ConfigEntry, hadBlankLine bool) bool {
+	switch {
+		case prevEntry.Kind == sshmkr_templates.SubHeaderLine:
+			// The hosts of a sub header are placed right under it
+			return currEntry.Kind == sshmkr_templates.MainHeaderLine || currEntry.Kind == sshmkr_templates.SubHeaderLine
+		case prevEntry.Kind == sshmkr_templates.MainHeaderLine:
+			return true
+		case currEntry.Kind == sshmkr_templates.MainHeaderLine || currEntry.Kind == sshmkr_templates.SubHeaderLine:
+			return true
+		case currEntry.IsBlock():
+			return prevEntry.Kind != sshmkr_templates.CommentLine || hadBlankLine
+		case prevEntry.IsBlock():
+			return true
+	}
+	return hadBlankLine
+}
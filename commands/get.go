@@ -1,35 +1,101 @@
 package sshmkr_commands
 
 import (
+	"encoding/json"
 	"fmt"
 	"os"
+	"strings"
 	"sshmkr/reader"
-	"github.com/kevinburke/ssh_config"
+	"sshmkr/templates"
+	"gopkg.in/yaml.v3"
 )
 
 // Prints out a specific host configuration out to standard output
-func GetSpecificHostConfig(hostname string, parsedConfig *ssh_config.Config) {
+// The output format can either be text (as it appears in the config), json or yaml
+func GetSpecificHostConfig(hostname string, includeCommented bool, outputFormat string, files *sshmkr_templates.ConfigFiles) error {
+	doc, hostIndex, _, err := FindSourceHost(hostname, includeCommented, "show", files)
+	if err != nil {
+		return err
+	}
+	return printHostConfig(hostIndex, outputFormat, doc)
+}
+
+// Helper method that prints out the host config at the given index of a file in the given output format
+func printHostConfig(hostIndex int, outputFormat string, doc *sshmkr_templates.ConfigDocument) error {
+	host := doc.Entries[hostIndex]
+	switch outputFormat {
+		case "", "text":
+			// Once we found the desired host, we print it out in its entirety
+			fmt.Println(host.GetHeaderLine().Key, "", host.GetHeaderLine().Value)
+			for _, option := range host.GetOptions() {
+				fmt.Println(option.Raw)
+			}
+		case "json":
+			jsonOutput, _ := json.MarshalIndent(GetHostDetails(hostIndex, doc), "", "  ")
+			fmt.Println(string(jsonOutput))
+		case "yaml":
+			printYAML(GetHostDetails(hostIndex, doc))
+		default:
+			return sshmkr_templates.NewConfigError(sshmkr_templates.ErrInvalidInput, "Output format", outputFormat, "is not supported! Available formats are: [text, json, yaml]")
+	}
+	return nil
+}
+
+// Helper method that prints out a value as a YAML document, indented the same way as the json output
+func printYAML(value interface{}) {
+	encoder := yaml.NewEncoder(os.Stdout)
+	encoder.SetIndent(2)
+	encoder.Encode(value)
+	encoder.Close()
+}
+
+// Finds the host that a command was given with the source flag
+// The action is what the command does with the host, and is used to tell the user what the hostname is needed for
+// Returns the file the host is in, its index in that file and the host itself
+func FindSourceHost(hostname string, includeCommented bool, action string, files *sshmkr_templates.ConfigFiles) (*sshmkr_templates.ConfigDocument, int, *sshmkr_templates.ConfigEntry, error) {
 	if len(hostname) <= 0 {
-		fmt.Println("Source flag is empty! Please pass in a valid hostname to show!")
-		os.Exit(-1)
+		return nil, -1, nil, sshmkr_templates.NewConfigError(sshmkr_templates.ErrInvalidInput, fmt.Sprintf("Source flag is empty! Please pass in a valid hostname to %s!", action))
 	}
 
-	for _, host := range parsedConfig.Hosts {
-		if host.Patterns[0].String() == hostname {
-			// Once we found the desired host, we print it out in its entirety and leave the statement
-			fmt.Println("Host ", hostname)
-			for _, node := range host.Nodes {
-				output := node.String()
+	doc, hostIndex, host := files.FindBlock(hostname, includeCommented)
+	if hostIndex == -1 {
+		return nil, -1, nil, sshmkr_templates.NewConfigError(sshmkr_templates.ErrHostNotFound, "Cannot find host", hostname, "in config. Typo maybe?")
+	}
+	return doc, hostIndex, host, nil
+}
 
-				if sshmkr_reader.CheckIfValid(output) {
-					fmt.Println(output)
-				}
-			}
-			return
-		}
+// Gathers everything about the host config at the given index of a file into a HostDetails object
+func GetHostDetails(hostIndex int, doc *sshmkr_templates.ConfigDocument) sshmkr_templates.HostDetails {
+	host := doc.Entries[hostIndex]
+	mainHeader, subHeader := doc.GetHeadersOf(hostIndex)
+	startLine, endLine := doc.GetLineRange(hostIndex)
+
+	blockKind := "host"
+	patterns := host.GetPatterns()
+	criteria := ""
+	if host.Kind == sshmkr_templates.MatchLine {
+		blockKind = "match"
+		patterns = []string{}
+		criteria = host.GetHeaderLine().Value
+	}
+
+	options := []sshmkr_templates.HostOption{}
+	for _, currOption := range host.GetOptions() {
+		comment := strings.TrimSpace(strings.TrimLeft(strings.TrimSpace(currOption.Comment), sshmkr_reader.COMMENT_IND))
+		options = append(options, sshmkr_templates.HostOption{Key: currOption.Key, Value: currOption.Value, Comment: comment})
 	}
 
-	// We only come here if we cannot find the host specified
-	fmt.Println("Cannot find host", hostname, "in config. Typo maybe?")
-	os.Exit(-1)
+	return sshmkr_templates.HostDetails{
+		Kind: blockKind,
+		Name: host.GetName(),
+		Patterns: patterns,
+		Criteria: criteria,
+		Options: options,
+		MainHeader: sshmkr_reader.TrimHeaderIndicator(mainHeader),
+		SubHeader: sshmkr_reader.TrimHeaderIndicator(subHeader),
+		StartLine: startLine,
+		EndLine: endLine,
+		Commented: host.IsCommented(),
+		File: doc.Path,
+	}
 }
@@ -0,0 +1,264 @@
+package sshmkr_commands
+
+import (
+	"strings"
+	"sshmkr/reader"
+	"sshmkr/templates"
+)
+
+// Adds a new main header, or a new sub header under a main header, to the config
+// A "Main Header/Sub Header" path creates the main header as well if it does not exist yet
+func AddHeader(headerPath string, files *sshmkr_templates.ConfigFiles) error {
+	mainName, subName, err := checkHeaderPath(headerPath)
+	if err != nil {
+		return err
+	} else if _, headerIndex, _ := findHeaderSection(headerPath, files); headerIndex != -1 {
+		return sshmkr_templates.NewConfigError(sshmkr_templates.ErrInvalidInput, "Header", headerPath, "already exists in config!")
+	}
+
+	EnsureHeaderSection(mainName, subName, files)
+	return nil
+}
+
+// Gives a main/sub header a new name
+func RenameHeader(headerPath string, newName string, files *sshmkr_templates.ConfigFiles) error {
+	mainName, subName, err := checkHeaderPath(headerPath)
+	if err != nil {
+		return err
+	} else if len(strings.TrimSpace(newName)) <= 0 || strings.Contains(newName, "/") {
+		return sshmkr_templates.NewConfigError(sshmkr_templates.ErrInvalidInput, "Name flag is empty or has a / in it! Please pass in a valid name for the header!")
+	}
+
+	doc, headerIndex, _, err := findExistingHeaderSection(headerPath, files)
+	if err != nil {
+		return err
+	}
+
+	// The new name cannot be used by another header at the same level
+	headerInd := sshmkr_reader.MAIN_HEADER_IND
+	newPath := strings.TrimSpace(newName)
+	if subName != "" {
+		headerInd = sshmkr_reader.SUB_HEADER_IND
+		newPath = mainName + "/" + newPath
+	}
+	if _, otherIndex, _ := findHeaderSection(newPath, files); otherIndex != -1 && !strings.EqualFold(newPath, headerPath) {
+		return sshmkr_templates.NewConfigError(sshmkr_templates.ErrInvalidInput, "Header", newPath, "already exists in config!")
+	}
+
+	doc.Entries[headerIndex].Lines[0].Raw = headerInd + " " + strings.TrimSpace(newName)
+	return nil
+}
+
+// Removes a main/sub header along with everything that lives under it
+// Headers that still have hosts under them are only removed if cascade is true, so hosts are never removed by accident
+// Returns the number of hosts that were removed with the header
+func RemoveHeader(headerPath string, cascade bool, files *sshmkr_templates.ConfigFiles) (int, error) {
+	if _, _, err := checkHeaderPath(headerPath); err != nil {
+		return 0, err
+	}
+	doc, headerIndex, sectionEnd, err := findExistingHeaderSection(headerPath, files)
+	if err != nil {
+		return 0, err
+	}
+
+	hostCount := 0
+	optionCount := 0
+	for _, currEntry := range doc.Entries[headerIndex+1:sectionEnd] {
+		if currEntry.IsBlock() {
+			hostCount = hostCount + 1
+		} else if currEntry.Kind == sshmkr_templates.OptionLine {
+			optionCount = optionCount + 1
+		}
+	}
+
+	// Global options under the header are not hosts, but they would be lost all the same
+	if hostCount > 0 && !cascade {
+		return 0, sshmkr_templates.NewConfigError(sshmkr_templates.ErrInvalidInput, "Header", headerPath, "still has", hostCount, "host(s) under it! Move them somewhere else first, or pass in -cascade to remove them too.")
+	} else if optionCount > 0 && !cascade {
+		return 0, sshmkr_templates.NewConfigError(sshmkr_templates.ErrInvalidInput, "Header", headerPath, "still has", optionCount, "global option(s) under it! Move them somewhere else first, or pass in -cascade to remove them too.")
+	}
+
+	removeSection(headerIndex, sectionEnd, doc)
+	return hostCount, nil
+}
+
+// Moves a main/sub header, along with everything under it, to right before or after another header
+// Main headers can only be placed around other main headers, and sub headers around other sub headers
+func MoveHeader(headerPath string, beforePath string, afterPath string, files *sshmkr_templates.ConfigFiles) error {
+	_, subName, err := checkHeaderPath(headerPath)
+	if err != nil {
+		return err
+	}
+
+	targetPath := beforePath
+	if (beforePath == "") == (afterPath == "") {
+		return sshmkr_templates.NewConfigError(sshmkr_templates.ErrInvalidInput, "Exactly one of the before and after flags has to be passed in!")
+	} else if afterPath != "" {
+		targetPath = afterPath
+	}
+
+	_, targetSubName, err := checkHeaderPath(targetPath)
+	if err != nil {
+		return err
+	} else if (subName == "") != (targetSubName == "") {
+		return sshmkr_templates.NewConfigError(sshmkr_templates.ErrInvalidInput, "Main headers can only be moved around main headers, and sub headers around sub headers!")
+	}
+
+	sourceDoc, headerIndex, sectionEnd, err := findExistingHeaderSection(headerPath, files)
+	if err != nil {
+		return err
+	}
+	targetDoc, targetIndex, _, err := findExistingHeaderSection(targetPath, files)
+	if err != nil {
+		return err
+	} else if sourceDoc == targetDoc && headerIndex == targetIndex {
+		return sshmkr_templates.NewConfigError(sshmkr_templates.ErrInvalidInput, "Cannot move header", headerPath, "around itself!")
+	}
+
+	// The target is found again after the section is taken out, since its index may have changed
+	movedEntries := removeSection(headerIndex, sectionEnd, sourceDoc)
+	targetDoc, targetIndex, targetEnd := findHeaderSection(targetPath, files)
+	if afterPath != "" {
+		targetIndex = targetEnd
+	}
+	insertSection(targetIndex, movedEntries, targetDoc)
+	return nil
+}
+
+// Finds the section of the given main/sub header, creating the headers that do not exist yet
+// New main headers are placed at the end of the main config, and new sub headers at the end of their main header
+// Returns the file the section is in and the index of its header (the sub header if one was given)
+func EnsureHeaderSection(mainName string, subName string, files *sshmkr_templates.ConfigFiles) (*sshmkr_templates.ConfigDocument, int) {
+	doc, mainIndex, _ := findHeaderSection(mainName, files)
+	if mainIndex == -1 {
+		doc = files.GetMainDoc()
+		mainIndex = insertSection(len(doc.Entries), []*sshmkr_templates.ConfigEntry{newHeaderEntry(sshmkr_templates.MainHeaderLine, mainName)}, doc)
+	}
+	if subName == "" {
+		return doc, mainIndex
+	}
+
+	if subDoc, subIndex, _ := findHeaderSection(mainName + "/" + subName, files); subIndex != -1 {
+		return subDoc, subIndex
+	}
+	return doc, insertSection(doc.FindMainSectionEnd(mainIndex), []*sshmkr_templates.ConfigEntry{newHeaderEntry(sshmkr_templates.SubHeaderLine, subName)}, doc)
+}
+
+// Finds the main and sub header lines of an existing "Main Header/Sub Header" path, so a host can be placed under it
+// Returns an error if the path has no sub header or either header does not exist
+func FindHeaderPath(headerPath string, files *sshmkr_templates.ConfigFiles) (string, string, error) {
+	mainName, subName, err := checkHeaderPath(headerPath)
+	if err != nil {
+		return "", "", err
+	} else if subName == "" {
+		return "", "", sshmkr_templates.NewConfigError(sshmkr_templates.ErrInvalidInput, "Hosts can only be placed under a sub header! Please pass in a \"Main Header/Sub Header\" path!")
+	}
+
+	mainDoc, mainIndex, _, err := findExistingHeaderSection(mainName, files)
+	if err != nil {
+		return "", "", err
+	}
+	subDoc, subIndex, _, err := findExistingHeaderSection(headerPath, files)
+	if err != nil {
+		return "", "", err
+	}
+	return mainDoc.Entries[mainIndex].Lines[0].Raw, subDoc.Entries[subIndex].Lines[0].Raw, nil
+}
+
+// Helper method that checks that a "Main Header/Sub Header" path was passed in
+// Returns the main and sub header names of the path
+func checkHeaderPath(headerPath string) (string, string, error) {
+	mainName, subName := sshmkr_reader.SplitHeaderPath(headerPath)
+	if mainName == "" || (strings.Contains(headerPath, "/") && subName == "") {
+		return "", "", sshmkr_templates.NewConfigError(sshmkr_templates.ErrInvalidInput, "Header flag is empty! Please pass in a valid \"Main Header\" or \"Main Header/Sub Header\" path!")
+	}
+	return mainName, subName, nil
+}
+
+// Helper method that finds the section of a main/sub header in any file
+// Returns the file the header is in, its index and the index its section ends at, or nil, -1 and -1 if it does not exist
+func findHeaderSection(headerPath string, files *sshmkr_templates.ConfigFiles) (*sshmkr_templates.ConfigDocument, int, int) {
+	mainName, subName := sshmkr_reader.SplitHeaderPath(headerPath)
+
+	for _, currDoc := range files.Docs {
+		for mainIndex, currEntry := range currDoc.Entries {
+			if currEntry.Kind != sshmkr_templates.MainHeaderLine || !strings.EqualFold(sshmkr_reader.TrimHeaderIndicator(currEntry.Lines[0].Raw), mainName) {
+				continue
+			}
+
+			mainEnd := currDoc.FindMainSectionEnd(mainIndex)
+			if subName == "" {
+				return currDoc, mainIndex, mainEnd
+			}
+			for subIndex := mainIndex + 1; subIndex < mainEnd; subIndex = subIndex + 1 {
+				subEntry := currDoc.Entries[subIndex]
+				if subEntry.Kind == sshmkr_templates.SubHeaderLine && strings.EqualFold(sshmkr_reader.TrimHeaderIndicator(subEntry.Lines[0].Raw), subName) {
+					return currDoc, subIndex, currDoc.FindSectionEnd(subIndex)
+				}
+			}
+		}
+	}
+	return nil, -1, -1
+}
+
+// Helper method that finds the section of a main/sub header, returning an error if it does not exist
+func findExistingHeaderSection(headerPath string, files *sshmkr_templates.ConfigFiles) (*sshmkr_templates.ConfigDocument, int, int, error) {
+	doc, headerIndex, sectionEnd := findHeaderSection(headerPath, files)
+	if headerIndex == -1 {
+		return nil, -1, -1, sshmkr_templates.NewConfigError(sshmkr_templates.ErrHeaderNotFound, "Cannot find header", headerPath, "in config. Typo maybe?")
+	}
+	return doc, headerIndex, sectionEnd, nil
+}
+
+// Helper method that creates a new main/sub header line
+func newHeaderEntry(kind sshmkr_templates.LineKind, name string) *sshmkr_templates.ConfigEntry {
+	headerInd := sshmkr_reader.MAIN_HEADER_IND
+	if kind == sshmkr_templates.SubHeaderLine {
+		headerInd = sshmkr_reader.SUB_HEADER_IND
+	}
+	headerLine := &sshmkr_templates.ConfigLine{Raw: headerInd + " " + strings.TrimSpace(name), Kind: kind}
+	return &sshmkr_templates.ConfigEntry{Kind: kind, Lines: []*sshmkr_templates.ConfigLine{headerLine}}
+}
+
+// Helper method that places a header section at the given index, with a blank line around it
+// Returns the index that the first entry of the section ended up at
+func insertSection(index int, sectionEntries []*sshmkr_templates.ConfigEntry, doc *sshmkr_templates.ConfigDocument) int {
+	// We place the section after the last non blank line before the index
+	insertIndex := index
+	for insertIndex > 0 && doc.Entries[insertIndex-1].Kind == sshmkr_templates.BlankLine {
+		insertIndex = insertIndex - 1
+	}
+
+	toInsert := []*sshmkr_templates.ConfigEntry{}
+	sectionStart := insertIndex
+	if insertIndex > 0 {
+		toInsert = append(toInsert, sshmkr_templates.NewBlankEntry())
+		sectionStart = sectionStart + 1
+	}
+	toInsert = append(toInsert, sectionEntries...)
+	if insertIndex == index && index < len(doc.Entries) {
+		toInsert = append(toInsert, sshmkr_templates.NewBlankEntry())
+	}
+
+	doc.InsertEntries(insertIndex, toInsert...)
+	return sectionStart
+}
+
+// Helper method that takes a header section out of a document, leaving a single blank line where it was
+// Returns the entries of the section, without the blank lines at its end
+func removeSection(headerIndex int, sectionEnd int, doc *sshmkr_templates.ConfigDocument) []*sshmkr_templates.ConfigEntry {
+	contentEnd := sectionEnd
+	for contentEnd - 1 > headerIndex && doc.Entries[contentEnd-1].Kind == sshmkr_templates.BlankLine {
+		contentEnd = contentEnd - 1
+	}
+
+	sectionEntries := append([]*sshmkr_templates.ConfigEntry{}, doc.Entries[headerIndex:contentEnd]...)
+	doc.Entries = append(doc.Entries[:headerIndex], doc.Entries[contentEnd:]...)
+
+	// The blank lines that were around the section are merged together
+	for headerIndex < len(doc.Entries) && doc.Entries[headerIndex].Kind == sshmkr_templates.BlankLine &&
+		(headerIndex == 0 || doc.Entries[headerIndex-1].Kind == sshmkr_templates.BlankLine) {
+		doc.RemoveEntry(headerIndex)
+	}
+	return sectionEntries
+}
@@ -0,0 +1,36 @@
+package sshmkr_commands
+
+import (
+	"testing"
+	"sshmkr/reader"
+	"sshmkr/templates"
+)
+
+func TestRemoveHeaderCountsHosts(t *testing.T) {
+	testCases := []struct {
+		name string
+		contents string
+		cascade bool
+		wantCount int
+		wantErr bool
+	}{
+		{"empty header", "#### Work\n\n#### Home\n", false, 0, false},
+		{"hosts without cascade", "#### Work\nHost web\n\tUser me\nHost db\n\tUser me\n", false, 0, true},
+		{"hosts with cascade", "#### Work\nHost web\n\tUser me\nHost db\n\tUser me\n", true, 2, false},
+		{"global options without cascade", "#### Work\nServerAliveInterval 60\n", false, 0, true},
+		{"global options are not hosts", "#### Work\nServerAliveInterval 60\nForwardAgent no\nHost web\n\tUser me\n", true, 1, false},
+	}
+
+	for _, currCase := range testCases {
+		t.Run(currCase.name, func(t *testing.T) {
+			files := &sshmkr_templates.ConfigFiles{Docs: []*sshmkr_templates.ConfigDocument{sshmkr_reader.ParseConfigDocument([]byte(currCase.contents))}}
+			gotCount, err := RemoveHeader("Work", currCase.cascade, files)
+			if (err != nil) != currCase.wantErr {
+				t.Fatalf("RemoveHeader() error = %v, want error %v", err, currCase.wantErr)
+			}
+			if gotCount != currCase.wantCount {
+				t.Errorf("RemoveHeader() = %d, want %d", gotCount, currCase.wantCount)
+			}
+		})
+	}
+}
@@ -0,0 +1,137 @@
+package sshmkr_commands
+
+import (
+	"fmt"
+	"io/ioutil"
+	"strings"
+	"sshmkr/reader"
+	"sshmkr/templates"
+)
+
+// Prints out every change that was recorded in the journal, newest first
+// The number in front of each change is how many undos it takes to revert it
+func PrintJournalHistory(entries []sshmkr_templates.JournalEntry, showDiff bool) {
+	if len(entries) == 0 {
+		fmt.Println("No changes have been recorded yet!")
+		return
+	}
+
+	undoNum := 0
+	for currIndex := len(entries) - 1; currIndex >= 0; currIndex = currIndex - 1 {
+		currEntry := entries[currIndex]
+
+		numbering := "-"
+		status := ""
+		if currEntry.Undone {
+			status = " (undone)"
+		} else {
+			undoNum = undoNum + 1
+			numbering = fmt.Sprint(undoNum)
+		}
+
+		fmt.Printf("%3s.) %s  %s%s\n", numbering, currEntry.Timestamp, strings.Join(append([]string{currEntry.Command}, currEntry.Args...), " "), status)
+		if showDiff {
+			for _, currChange := range currEntry.Files {
+				fmt.Println(currChange.Diff)
+			}
+		}
+	}
+}
+
+// Data struct that holds the changes of the journal that an undo reverts, along with what each file is reverted to
+type undoPlan struct {
+	entries []sshmkr_templates.JournalEntry
+	undoIndexes []int					// Index of each entry to revert, newest first
+	paths []string						// Every file that is reverted, in the order they are first reverted
+	currContents map[string]string		// Contents of each file on disk right now
+	undoneContents map[string]string	// Contents of each file once every change is reverted
+}
+
+// Reverts the last N changes that were recorded in the journal
+// Every change is checked before anything is written, and nothing is reverted if the config was changed outside of sshmkr
+// since one of the changes was made
+func UndoJournalEntries(configLoc string, undoCount int, backupCount int) error {
+	plan, err := planUndo(configLoc, undoCount)
+	if err != nil {
+		return err
+	}
+
+	for _, entryIndex := range plan.undoIndexes {
+		undoEntry := plan.entries[entryIndex]
+		for _, currChange := range undoEntry.Files {
+			if err := sshmkr_reader.WriteToConfigFile(currChange.Path, currChange.Before, backupCount); err != nil {
+				return err
+			}
+		}
+		plan.entries[entryIndex].Undone = true
+		if err := sshmkr_reader.WriteJournal(configLoc, plan.entries); err != nil {
+			return err
+		}
+
+		fmt.Println("Sucessfully undid", strings.Join(append([]string{undoEntry.Command}, undoEntry.Args...), " "), "from", undoEntry.Timestamp, "!")
+	}
+	return nil
+}
+
+// Gets what undoing the last N changes of the journal would do, without writing anything
+// Each file that would be reverted is a document of its reverted contents, with the contents on disk as where it started from,
+// so the undo can be previewed the same way as the changes of any other command
+func GetUndoChanges(configLoc string, undoCount int) (*sshmkr_templates.ConfigFiles, error) {
+	plan, err := planUndo(configLoc, undoCount)
+	if err != nil {
+		return nil, err
+	}
+
+	undoFiles := &sshmkr_templates.ConfigFiles{Docs: []*sshmkr_templates.ConfigDocument{}, Includes: map[*sshmkr_templates.ConfigLine][]*sshmkr_templates.ConfigDocument{}}
+	for _, currPath := range plan.paths {
+		undoneDoc := sshmkr_reader.ParseConfigDocument([]byte(plan.undoneContents[currPath]))
+		undoneDoc.Path = currPath
+		undoneDoc.OrigContents = plan.currContents[currPath]
+		undoFiles.Docs = append(undoFiles.Docs, undoneDoc)
+	}
+	return undoFiles, nil
+}
+
+// Helper method that finds the last N changes of the journal that have not been undone yet, and works out what each file is reverted to
+// Each change is checked against what the file would hold once the newer changes are reverted, so an undo is never half done
+func planUndo(configLoc string, undoCount int) (undoPlan, error) {
+	if undoCount <= 0 {
+		return undoPlan{}, sshmkr_templates.NewConfigError(sshmkr_templates.ErrInvalidInput, "Number of changes to undo must be at least 1!")
+	}
+
+	entries, err := sshmkr_reader.ReadJournal(configLoc)
+	if err != nil {
+		return undoPlan{}, err
+	}
+	plan := undoPlan{entries: entries, currContents: map[string]string{}, undoneContents: map[string]string{}}
+
+	entryIndex := len(entries) - 1
+	for undoNum := 0; undoNum < undoCount; undoNum = undoNum + 1 {
+		for entryIndex >= 0 && entries[entryIndex].Undone {
+			entryIndex = entryIndex - 1
+		}
+		if entryIndex < 0 {
+			return undoPlan{}, sshmkr_templates.NewConfigError(sshmkr_templates.ErrInvalidInput, "There are no more changes to undo!")
+		}
+		undoEntry := entries[entryIndex]
+
+		for _, currChange := range undoEntry.Files {
+			if _, hasRead := plan.undoneContents[currChange.Path]; !hasRead {
+				currContents, err := ioutil.ReadFile(currChange.Path)
+				if err != nil {
+					return undoPlan{}, sshmkr_templates.NewConfigError(sshmkr_templates.ErrFileAccess, "The config location:", currChange.Path, "cannot be read!")
+				}
+				plan.paths = append(plan.paths, currChange.Path)
+				plan.currContents[currChange.Path] = string(currContents)
+				plan.undoneContents[currChange.Path] = string(currContents)
+			}
+			if sshmkr_reader.HashContents(plan.undoneContents[currChange.Path]) != currChange.AfterHash {
+				return undoPlan{}, sshmkr_templates.NewConfigError(sshmkr_templates.ErrConcurrentChange, "Cannot undo", undoEntry.Command, "from", undoEntry.Timestamp, "since", currChange.Path, "was changed outside of sshmkr afterwards!")
+			}
+			plan.undoneContents[currChange.Path] = currChange.Before
+		}
+		plan.undoIndexes = append(plan.undoIndexes, entryIndex)
+		entryIndex = entryIndex - 1
+	}
+	return plan, nil
+}
@@ -0,0 +1,77 @@
+package sshmkr_commands
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"sshmkr/reader"
+	"sshmkr/templates"
+)
+
+// Helper method that writes a new version of the config and records it in the journal, the same way a command does
+func writeJournaledChange(t *testing.T, configLoc string, command string, contents string) {
+	origContents, err := ioutil.ReadFile(configLoc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	changedDoc := sshmkr_reader.ParseConfigDocument([]byte(contents))
+	changedDoc.Path = configLoc
+	changedDoc.OrigContents = string(origContents)
+
+	if err := sshmkr_reader.WriteToConfigFile(configLoc, contents, 0); err != nil {
+		t.Fatal(err)
+	}
+	if err := sshmkr_reader.RecordJournalEntry(configLoc, []string{command}, []*sshmkr_templates.ConfigDocument{changedDoc}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestUndoJournalEntries(t *testing.T) {
+	tempDir, err := ioutil.TempDir("", "sshmkr-history")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.RemoveAll(tempDir) })
+	configLoc := filepath.Join(tempDir, "config")
+	if err := ioutil.WriteFile(configLoc, []byte("Host a\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	writeJournaledChange(t, configLoc, "add", "Host a\n\nHost b\n")
+	writeJournaledChange(t, configLoc, "add", "Host a\n\nHost b\n\nHost c\n")
+
+	// Previewing both undos goes from the contents on disk to the contents before the first change, without writing anything
+	undoFiles, err := GetUndoChanges(configLoc, 2)
+	if err != nil {
+		t.Fatalf("GetUndoChanges() error = %v", err)
+	}
+	changedDocs := undoFiles.GetChangedDocs()
+	if len(changedDocs) != 1 || changedDocs[0].OrigContents != "Host a\n\nHost b\n\nHost c\n" || changedDocs[0].String() != "Host a\n" {
+		t.Fatalf("GetUndoChanges() = %+v, want the config reverted to Host a", changedDocs)
+	}
+	if _, err := GetUndoChanges(configLoc, 3); !errors.Is(err, sshmkr_templates.ErrInvalidInput) {
+		t.Errorf("GetUndoChanges() past the first change error = %v, want %v", err, sshmkr_templates.ErrInvalidInput)
+	}
+
+	// A config that was changed outside of sshmkr is never reverted
+	if err := ioutil.WriteFile(configLoc, []byte("Host a\n\nHost b\n\nHost c\n\nHost d\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := GetUndoChanges(configLoc, 1); !errors.Is(err, sshmkr_templates.ErrConcurrentChange) {
+		t.Errorf("GetUndoChanges() after an outside change error = %v, want %v", err, sshmkr_templates.ErrConcurrentChange)
+	}
+	if err := UndoJournalEntries(configLoc, 1, 0); !errors.Is(err, sshmkr_templates.ErrConcurrentChange) {
+		t.Errorf("UndoJournalEntries() after an outside change error = %v, want %v", err, sshmkr_templates.ErrConcurrentChange)
+	}
+
+	if err := ioutil.WriteFile(configLoc, []byte("Host a\n\nHost b\n\nHost c\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := UndoJournalEntries(configLoc, 2, 0); err != nil {
+		t.Fatalf("UndoJournalEntries() error = %v", err)
+	}
+	if contents, _ := ioutil.ReadFile(configLoc); string(contents) != "Host a\n" {
+		t.Errorf("UndoJournalEntries() left %q, want %q", contents, "Host a\n")
+	}
+}
@@ -0,0 +1,244 @@
+package sshmkr_commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"sshmkr/reader"
+	"sshmkr/templates"
+)
+
+// Data struct that holds the state of linting a ssh config
+type configLinter struct {
+	files *sshmkr_templates.ConfigFiles
+	issues []sshmkr_templates.LintIssue
+	seenBlocks []lintedBlock		// Active Host/Match blocks, in the order that ssh reads them
+	ignoreUnknown []string			// Patterns from IgnoreUnknown, for keywords that should not be reported
+}
+
+// Data struct that holds a Host/Match block that was already gone through, along with where it is
+type lintedBlock struct {
+	entry *sshmkr_templates.ConfigEntry
+	file string
+	line int
+}
+
+// Checks the ssh config (and every file it includes) for problems and prints them out
+// The output format can either be text, json or yaml
+// Returns the number of problems that were found
+func LintConfig(outputFormat string, files *sshmkr_templates.ConfigFiles) (int, error) {
+	linter := &configLinter{files: files}
+	linter.walkDoc(files.GetMainDoc())
+	for _, currDoc := range files.Docs {
+		linter.checkHeaders(currDoc)
+	}
+
+	// Problems are listed in the order of the files, and then by line
+	docOrder := map[string]int{}
+	for currIndex, currDoc := range files.Docs {
+		docOrder[currDoc.Path] = currIndex
+	}
+	sort.SliceStable(linter.issues, func(i, j int) bool {
+		if linter.issues[i].File != linter.issues[j].File {
+			return docOrder[linter.issues[i].File] < docOrder[linter.issues[j].File]
+		}
+		return linter.issues[i].Line < linter.issues[j].Line
+	})
+
+	switch outputFormat {
+		case "", "text":
+			for _, currIssue := range linter.issues {
+				fmt.Printf("%s:%d: %s: %s\n", currIssue.File, currIssue.Line, currIssue.Rule, currIssue.Message)
+			}
+			if len(linter.issues) == 0 {
+				fmt.Println("No problems found in the ssh_config!")
+			} else {
+				fmt.Println("")
+				fmt.Println("Found", len(linter.issues), "problem(s) in the ssh_config!")
+			}
+		case "json":
+			jsonOutput, _ := json.MarshalIndent(linter.issues, "", "  ")
+			if len(linter.issues) == 0 {
+				jsonOutput = []byte("[]")
+			}
+			fmt.Println(string(jsonOutput))
+		case "yaml":
+			printYAML(linter.issues)
+		default:
+			return 0, sshmkr_templates.NewConfigError(sshmkr_templates.ErrInvalidInput, "Output format", outputFormat, "is not supported! Available formats are: [text, json, yaml]")
+	}
+	return len(linter.issues), nil
+}
+
+// Helper method that records a problem that was found
+func (linter *configLinter) addIssue(rule string, file string, line int, name string, message string) {
+	linter.issues = append(linter.issues, sshmkr_templates.LintIssue{Rule: rule, File: file, Line: line, Name: name, Message: message})
+}
+
+// Helper method that goes through the blocks and options of a file in the order that ssh reads them
+// Included files are gone through right where they are included, and start inside a block if the Include line was in one
+func (linter *configLinter) walkDoc(doc *sshmkr_templates.ConfigDocument) {
+	for currIndex, currEntry := range doc.Entries {
+		startLine, _ := doc.GetLineRange(currIndex)
+		optionLines := currEntry.Lines
+		blockName := ""
+
+		if currEntry.IsBlock() {
+			if currEntry.IsCommented() {
+				continue
+			}
+			currBlock := lintedBlock{entry: currEntry, file: doc.Path, line: startLine}
+			linter.checkDuplicateBlock(currBlock)
+			linter.checkShadowedBlock(currBlock)
+			linter.seenBlocks = append(linter.seenBlocks, currBlock)
+
+			// Global options are fine on their own, but every host is expected to live under a main header
+			blockName = currEntry.GetName()
+			if mainHeader, _ := doc.GetHeadersOf(currIndex); mainHeader == "" {
+				linter.addIssue("host-outside-header", doc.Path, startLine, blockName,
+					fmt.Sprintf("%s is not under any %s main header", blockName, sshmkr_reader.MAIN_HEADER_IND))
+			}
+			optionLines = currEntry.Lines[1:]
+			startLine = startLine + 1
+		}
+
+		for lineOffset, currLine := range optionLines {
+			if currLine.Kind != sshmkr_templates.OptionLine || currLine.Commented {
+				continue
+			}
+
+			if includedDocs, isInclude := linter.files.Includes[currLine]; isInclude {
+				for _, includedDoc := range includedDocs {
+					linter.walkDoc(includedDoc)
+				}
+				continue
+			}
+
+			if strings.EqualFold(currLine.Key, "IgnoreUnknown") {
+				linter.ignoreUnknown = append(linter.ignoreUnknown, strings.Split(currLine.Value, ",")...)
+			}
+
+			if !sshmkr_reader.IsKnownKeyword(currLine.Key) && !MatchesPatternList(currLine.Key, linter.ignoreUnknown) {
+				message := fmt.Sprintf("%s is not a keyword that ssh understands", currLine.Key)
+				if suggestion := sshmkr_reader.SuggestKeyword(currLine.Key); suggestion != "" {
+					message = fmt.Sprintf("%s, did you mean %s?", message, suggestion)
+				}
+				linter.addIssue("unknown-keyword", doc.Path, startLine + lineOffset, blockName, message)
+			}
+		}
+	}
+}
+
+// Helper method that checks if a block goes by the same name as a block that came before it
+// Only the first block is ever found by commands like delete and comment, so the others are easy to miss
+func (linter *configLinter) checkDuplicateBlock(block lintedBlock) {
+	names := []string{block.entry.GetName()}
+	if block.entry.Kind == sshmkr_templates.HostLine {
+		names = block.entry.GetPatterns()
+	}
+
+	for _, currName := range names {
+		if strings.HasPrefix(currName, "!") {
+			continue
+		}
+
+		for _, seenBlock := range linter.seenBlocks {
+			if seenBlock.entry.Kind == block.entry.Kind && seenBlock.entry.IsNamed(currName) {
+				linter.addIssue("duplicate-host", block.file, block.line, block.entry.GetName(),
+					fmt.Sprintf("%s is already defined at %s:%d, and commands only change the first one", currName, seenBlock.file, seenBlock.line))
+				break
+			}
+		}
+	}
+}
+
+// Helper method that checks if an earlier wildcard Host block already sets options of a host
+// ssh uses the first value it finds, so the values in the later block are never used
+func (linter *configLinter) checkShadowedBlock(block lintedBlock) {
+	if block.entry.Kind != sshmkr_templates.HostLine {
+		return
+	}
+
+	for _, seenBlock := range linter.seenBlocks {
+		if seenBlock.entry.Kind != sshmkr_templates.HostLine || !hasWildcardPattern(seenBlock.entry) {
+			continue
+		}
+
+		for _, currPattern := range block.entry.GetPatterns() {
+			if strings.HasPrefix(currPattern, "!") || strings.ContainsAny(currPattern, "*?") || !MatchesPatternList(currPattern, seenBlock.entry.GetPatterns()) {
+				continue
+			}
+
+			shadowedKeys := []string{}
+			for _, currOption := range block.entry.GetOptions() {
+				if seenBlock.entry.GetOption(currOption.Key) != "" && !multiValueKeywords[strings.ToLower(currOption.Key)] {
+					shadowedKeys = append(shadowedKeys, currOption.Key)
+				}
+			}
+			if len(shadowedKeys) > 0 {
+				linter.addIssue("shadowed-host", block.file, block.line, block.entry.GetName(),
+					fmt.Sprintf("%s is matched by Host %s at %s:%d first, so its %s value(s) are never used", currPattern,
+						seenBlock.entry.GetHeaderLine().Value, seenBlock.file, seenBlock.line, strings.Join(shadowedKeys, ", ")))
+			}
+			break
+		}
+	}
+}
+
+// Helper method that checks the main and sub headers of a file for orphan sub headers and empty sections
+func (linter *configLinter) checkHeaders(doc *sshmkr_templates.ConfigDocument) {
+	mainIndex, subIndex := -1, -1
+	mainHasContent, subHasContent := false, false
+
+	// Reports the section that is currently open if nothing was placed in it
+	closeSub := func() {
+		if subIndex != -1 && !subHasContent {
+			startLine, _ := doc.GetLineRange(subIndex)
+			linter.addIssue("empty-header", doc.Path, startLine, "", fmt.Sprintf("Sub header %s has no hosts under it",
+				sshmkr_reader.TrimHeaderIndicator(doc.Entries[subIndex].Lines[0].Raw)))
+		}
+		subIndex = -1
+	}
+	closeMain := func() {
+		if mainIndex != -1 && !mainHasContent {
+			startLine, _ := doc.GetLineRange(mainIndex)
+			linter.addIssue("empty-header", doc.Path, startLine, "", fmt.Sprintf("Main header %s has no sub headers or hosts under it",
+				sshmkr_reader.TrimHeaderIndicator(doc.Entries[mainIndex].Lines[0].Raw)))
+		}
+		mainIndex = -1
+	}
+
+	for currIndex, currEntry := range doc.Entries {
+		switch currEntry.Kind {
+			case sshmkr_templates.MainHeaderLine:
+				closeSub()
+				closeMain()
+				mainIndex, mainHasContent = currIndex, false
+			case sshmkr_templates.SubHeaderLine:
+				closeSub()
+				subIndex, subHasContent = currIndex, false
+				if mainIndex != -1 {
+					mainHasContent = true
+				} else {
+					startLine, _ := doc.GetLineRange(currIndex)
+					linter.addIssue("orphan-sub-header", doc.Path, startLine, "", fmt.Sprintf("Sub header %s is not under any main header",
+						sshmkr_reader.TrimHeaderIndicator(currEntry.Lines[0].Raw)))
+				}
+			case sshmkr_templates.HostLine, sshmkr_templates.MatchLine:
+				mainHasContent, subHasContent = true, true
+		}
+	}
+	closeSub()
+	closeMain()
+}
+
+// Helper method that checks if any of the patterns of a Host block has a wildcard in it
+func hasWildcardPattern(entry *sshmkr_templates.ConfigEntry) bool {
+	for _, currPattern := range entry.GetPatterns() {
+		if !strings.HasPrefix(currPattern, "!") && strings.ContainsAny(currPattern, "*?") {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,44 @@
+package sshmkr_commands
+
+import (
+	"testing"
+	"sshmkr/reader"
+	"sshmkr/templates"
+)
+
+func TestLintHostOutsideHeader(t *testing.T) {
+	testCases := []struct {
+		name string
+		contents string
+		wantLines []int
+	}{
+		{"global options only", "ServerAliveInterval 60\nInclude conf.d/*\n", []int{}},
+		{"hosts under headers", "ServerAliveInterval 60\n\n#### Work\n## Servers\nHost web\n\tUser me\n", []int{}},
+		{"host before any header", "ServerAliveInterval 60\n\nHost loose\n\tUser me\n\n#### Work\nHost web\n\tUser me\n", []int{3}},
+		{"match block without headers", "Match host web\n\tUser me\n", []int{1}},
+		{"commented out host", "#Host old\n#\tUser me\n", []int{}},
+	}
+
+	for _, currCase := range testCases {
+		t.Run(currCase.name, func(t *testing.T) {
+			files := &sshmkr_templates.ConfigFiles{Docs: []*sshmkr_templates.ConfigDocument{sshmkr_reader.ParseConfigDocument([]byte(currCase.contents))}}
+			linter := &configLinter{files: files}
+			linter.walkDoc(files.GetMainDoc())
+
+			gotLines := []int{}
+			for _, currIssue := range linter.issues {
+				if currIssue.Rule == "host-outside-header" {
+					gotLines = append(gotLines, currIssue.Line)
+				}
+			}
+			if len(gotLines) != len(currCase.wantLines) {
+				t.Fatalf("host-outside-header reported on lines %v, want %v", gotLines, currCase.wantLines)
+			}
+			for currIndex := range gotLines {
+				if gotLines[currIndex] != currCase.wantLines[currIndex] {
+					t.Errorf("host-outside-header reported on lines %v, want %v", gotLines, currCase.wantLines)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,118 @@
+package sshmkr_commands
+
+import (
+	"fmt"
+	"strings"
+	"sshmkr/reader"
+	"sshmkr/templates"
+)
+
+// Prints out every main header, sub header and the hosts under them as a tree
+// The filter can either be a main header name or a "Main Header/Sub Header" path
+func ListConfigTree(headerFilter string, showCommented bool, showSummary bool, headers []sshmkr_templates.HeaderBlock) error {
+	mainFilter, subFilter := sshmkr_reader.SplitHeaderPath(headerFilter)
+	foundHeader := false
+
+	for _, currHeader := range headers {
+		mainHeaderName := sshmkr_reader.TrimHeaderIndicator(currHeader.GetMainHeader())
+		if mainFilter != "" && !strings.EqualFold(mainFilter, mainHeaderName) {
+			continue
+		}
+		foundHeader = true
+
+		if mainHeaderName == "" {
+			mainHeaderName = "(no header)"
+		}
+		if len(headers) > 0 && currHeader.File != headers[0].File {
+			// Headers from included files are labeled with the file they came from
+			mainHeaderName = fmt.Sprintf("%s  (%s)", mainHeaderName, currHeader.File)
+		}
+		fmt.Println(mainHeaderName)
+
+		// We only print out the sub headers that are not filtered out
+		subIndexes := []int{}
+		for subIndex, currSubHeader := range currHeader.GetSubHeaders() {
+			if subFilter == "" || strings.EqualFold(subFilter, sshmkr_reader.TrimHeaderIndicator(currSubHeader)) {
+				subIndexes = append(subIndexes, subIndex)
+			}
+		}
+
+		for currIndex, subIndex := range subIndexes {
+			subHeaderName := sshmkr_reader.TrimHeaderIndicator(currHeader.GetSubHeaders()[subIndex])
+			hosts := filterHostSummaries(currHeader.GetSubHeaderHosts(subIndex), showCommented)
+			isLastSub := currIndex + 1 == len(subIndexes)
+
+			if subHeaderName == "" {
+				// Hosts directly under a main header are drawn at the same level as the sub headers
+				for hostIndex, currHost := range hosts {
+					isLastHost := isLastSub && hostIndex + 1 == len(hosts)
+					fmt.Printf("%s%s\n", treeBranch(isLastHost), formatHostSummary(currHost, showSummary))
+				}
+				continue
+			}
+
+			fmt.Printf("%s%s\n", treeBranch(isLastSub), subHeaderName)
+			for hostIndex, currHost := range hosts {
+				fmt.Printf("%s%s%s\n", treeIndent(isLastSub), treeBranch(hostIndex + 1 == len(hosts)), formatHostSummary(currHost, showSummary))
+			}
+		}
+		fmt.Println("")
+	}
+
+	if headerFilter != "" && !foundHeader {
+		return sshmkr_templates.NewConfigError(sshmkr_templates.ErrHeaderNotFound, "Cannot find header", headerFilter, "in config. Typo maybe?")
+	}
+	return nil
+}
+
+// Helper method that removes commented hosts from the passed in list, unless we want to show them
+func filterHostSummaries(hosts []sshmkr_templates.HostSummary, showCommented bool) []sshmkr_templates.HostSummary {
+	filteredHosts := []sshmkr_templates.HostSummary{}
+	for _, currHost := range hosts {
+		if !currHost.Commented || showCommented {
+			filteredHosts = append(filteredHosts, currHost)
+		}
+	}
+	return filteredHosts
+}
+
+// Helper method that formats a single host in the tree
+func formatHostSummary(host sshmkr_templates.HostSummary, showSummary bool) string {
+	output := host.Name
+	if host.Commented {
+		output = output + " (commented)"
+	}
+
+	if showSummary {
+		summary := []string{}
+		if host.Hostname != "" {
+			summary = append(summary, fmt.Sprintf("Hostname: %s", host.Hostname))
+		}
+		if host.User != "" {
+			summary = append(summary, fmt.Sprintf("User: %s", host.User))
+		}
+		if host.Port != "" {
+			summary = append(summary, fmt.Sprintf("Port: %s", host.Port))
+		}
+		if len(summary) > 0 {
+			output = fmt.Sprintf("%s  [ %s ]", output, strings.Join(summary, ", "))
+		}
+	}
+	return output
+}
+
+// Helper method that returns the branch drawn before an item in the tree
+func treeBranch(isLast bool) string {
+	if isLast {
+		return "└── "
+	}
+	return "├── "
+}
+
+// Helper method that returns the indentation for the children of an item in the tree
+func treeIndent(isLast bool) string {
+	if isLast {
+		return "    "
+	}
+	return "│   "
+}
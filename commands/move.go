@@ -0,0 +1,37 @@
+package sshmkr_commands
+
+import (
+	"strings"
+	"sshmkr/reader"
+	"sshmkr/templates"
+)
+
+// Moves a host config, along with the comments attached to it, to the end of another sub header's section
+// The host can be moved between the main config and any file it includes
+func MoveHostConfig(hostname string, mainHeader string, subHeader string, files *sshmkr_templates.ConfigFiles) error {
+	doc, hostIndex, host, err := FindSourceHost(hostname, false, "move", files)
+	if err != nil {
+		return err
+	}
+
+	currMainHeader, currSubHeader := doc.GetHeadersOf(hostIndex)
+	targetDoc, _ := files.FindSubHeader(mainHeader, subHeader)
+	if targetDoc == doc && strings.EqualFold(sshmkr_reader.TrimHeaderIndicator(currMainHeader), sshmkr_reader.TrimHeaderIndicator(mainHeader)) &&
+		strings.EqualFold(sshmkr_reader.TrimHeaderIndicator(currSubHeader), sshmkr_reader.TrimHeaderIndicator(subHeader)) {
+		return sshmkr_templates.NewConfigError(sshmkr_templates.ErrInvalidInput, "Host", hostname, "is already under", sshmkr_reader.TrimHeaderIndicator(mainHeader) + "/" + sshmkr_reader.TrimHeaderIndicator(subHeader), "!")
+	}
+
+	// The header is checked before the host is taken out, so nothing is changed if it is not valid
+	if len(sshmkr_reader.TrimHeaderIndicator(mainHeader)) <= 0 || len(sshmkr_reader.TrimHeaderIndicator(subHeader)) <= 0 {
+		return sshmkr_templates.NewConfigError(sshmkr_templates.ErrHeaderNotFound, "Main or sub header is empty! Please select a valid header to place the host under!")
+	}
+
+	// The host is taken out first, so the index of the sub header is found after the file has changed
+	RemoveEntryWithSpacing(hostIndex, doc)
+	targetDoc, subHeaderIndex, err := findSelectedSubHeader(mainHeader, subHeader, files)
+	if err != nil {
+		return err
+	}
+	InsertIntoSection(subHeaderIndex, []*sshmkr_templates.ConfigEntry{host}, targetDoc)
+	return nil
+}
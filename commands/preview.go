@@ -0,0 +1,22 @@
+package sshmkr_commands
+
+import (
+	"fmt"
+	"sshmkr/diff"
+	"sshmkr/templates"
+)
+
+// Prints out what would be written to each changed file, without writing anything
+// With showDiff, a unified diff of each file is printed, otherwise only the names of the files are
+// Returns true if any file would be changed
+func PreviewChanges(files *sshmkr_templates.ConfigFiles, showDiff bool) bool {
+	changedDocs := files.GetChangedDocs()
+	for _, currDoc := range changedDocs {
+		if showDiff {
+			fmt.Print(sshmkr_diff.UnifiedDiff(currDoc.Path, currDoc.Path, currDoc.OrigContents, currDoc.String()))
+		} else {
+			fmt.Println(currDoc.Path, "would be changed")
+		}
+	}
+	return len(changedDocs) > 0
+}
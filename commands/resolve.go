@@ -0,0 +1,263 @@
+package sshmkr_commands
+
+import (
+	"fmt"
+	"os/user"
+	"strings"
+	"sshmkr/reader"
+	"sshmkr/templates"
+)
+
+// Keywords that collect every value that is found instead of only keeping the first one, in lowercase
+var multiValueKeywords = map[string]bool{
+	"certificatefile": true, "dynamicforward": true, "identityfile": true,
+	"localforward": true, "remoteforward": true, "sendenv": true, "setenv": true,
+}
+
+// Data struct that holds a single option value, along with where it came from
+type resolvedOption struct {
+	Key string
+	Value string
+	File string
+	Line int
+	Block string
+	ShadowedBy *resolvedOption	// The option that took priority over this one, if any
+}
+
+// Data struct that holds the state of resolving the options of a hostname
+type hostResolver struct {
+	originalHost string
+	files *sshmkr_templates.ConfigFiles
+	options []*resolvedOption		// Options that ssh would use, in the order they were found
+	shadowed []*resolvedOption		// Options that were ignored since an earlier one took priority
+	notes []string					// Anything about the config that could not be evaluated the way ssh would
+}
+
+// Prints out the options that ssh would use when connecting to the given hostname
+// This follows the same rules as ssh: every Host/Match block that matches is used and the first value of each keyword wins
+// With explain, each value is followed by where it came from, and the values that were shadowed are listed too
+func ResolveHostConfig(hostname string, explain bool, files *sshmkr_templates.ConfigFiles) error {
+	if len(hostname) <= 0 {
+		return sshmkr_templates.NewConfigError(sshmkr_templates.ErrInvalidInput, "Hostname is empty! Please pass in a valid hostname to resolve!")
+	}
+
+	resolver := &hostResolver{originalHost: hostname, files: files}
+	resolver.walkDoc(files.GetMainDoc(), true, "global")
+
+	// ssh always connects to a hostname, which is the name that was passed in if no Hostname was set
+	if resolver.getValue("Hostname") == "" {
+		resolver.options = append([]*resolvedOption{&resolvedOption{Key: "Hostname", Value: hostname, Block: "default"}}, resolver.options...)
+	}
+
+	for _, currOption := range resolver.options {
+		if explain {
+			fmt.Printf("%s %s\t# %s\n", currOption.Key, currOption.Value, describeOptionSource(currOption))
+		} else {
+			fmt.Println(currOption.Key, currOption.Value)
+		}
+	}
+
+	if explain && len(resolver.shadowed) > 0 {
+		fmt.Println("")
+		fmt.Println("~ Shadowed Values ~")
+		for _, currOption := range resolver.shadowed {
+			fmt.Printf("%s %s\t# %s, shadowed by %s\n", currOption.Key, currOption.Value, describeOptionSource(currOption), describeOptionSource(currOption.ShadowedBy))
+		}
+	}
+
+	if explain && len(resolver.notes) > 0 {
+		fmt.Println("")
+		fmt.Println("~ Notes ~")
+		for _, currNote := range resolver.notes {
+			fmt.Println(currNote)
+		}
+	}
+	return nil
+}
+
+// Helper method that goes through a file in order, using every option that lives in a matching block
+// Included files start with the active state of the line that includes them, the same way ssh does
+func (resolver *hostResolver) walkDoc(doc *sshmkr_templates.ConfigDocument, isActive bool, blockName string) {
+	for currIndex, currEntry := range doc.Entries {
+		startLine, _ := doc.GetLineRange(currIndex)
+		optionLines := currEntry.Lines
+
+		if currEntry.IsBlock() {
+			if currEntry.IsCommented() {
+				continue
+			}
+			isActive = resolver.matchesBlock(currEntry)
+			blockName = fmt.Sprintf("%s %s", currEntry.GetHeaderLine().Key, currEntry.GetHeaderLine().Value)
+			optionLines = currEntry.Lines[1:]
+			startLine = startLine + 1
+		}
+
+		for lineOffset, currLine := range optionLines {
+			if currLine.Kind != sshmkr_templates.OptionLine || currLine.Commented {
+				continue
+			}
+
+			if includedDocs, isInclude := resolver.files.Includes[currLine]; isInclude {
+				for _, includedDoc := range includedDocs {
+					resolver.walkDoc(includedDoc, isActive, blockName)
+				}
+			} else if isActive {
+				resolver.useOption(&resolvedOption{Key: currLine.Key, Value: currLine.Value, File: doc.Path, Line: startLine + lineOffset, Block: blockName})
+			}
+		}
+	}
+}
+
+// Helper method that keeps an option if its keyword was not set yet
+func (resolver *hostResolver) useOption(option *resolvedOption) {
+	if strings.EqualFold(option.Key, "Include") {
+		return
+	}
+
+	for _, currOption := range resolver.options {
+		if strings.EqualFold(currOption.Key, option.Key) && !multiValueKeywords[strings.ToLower(option.Key)] {
+			option.ShadowedBy = currOption
+			resolver.shadowed = append(resolver.shadowed, option)
+			return
+		}
+	}
+	resolver.options = append(resolver.options, option)
+}
+
+// Helper method that gets the value that was kept for a keyword so far
+func (resolver *hostResolver) getValue(key string) string {
+	for _, currOption := range resolver.options {
+		if strings.EqualFold(currOption.Key, key) {
+			return currOption.Value
+		}
+	}
+	return ""
+}
+
+// Helper method that checks if a Host/Match block applies to the hostname being resolved
+func (resolver *hostResolver) matchesBlock(block *sshmkr_templates.ConfigEntry) bool {
+	if block.Kind == sshmkr_templates.HostLine {
+		return MatchesPatternList(resolver.originalHost, block.GetPatterns())
+	}
+
+	// Every criteria of a Match line has to match for the block to apply
+	criteria := sshmkr_reader.SplitConfigValue(block.GetHeaderLine().Value)
+	for currIndex := 0; currIndex < len(criteria); currIndex = currIndex + 1 {
+		keyword := strings.ToLower(criteria[currIndex])
+		isNegated := strings.HasPrefix(keyword, "!")
+		keyword = strings.TrimPrefix(keyword, "!")
+
+		argument := ""
+		if keyword != "all" && keyword != "canonical" && keyword != "final" && currIndex + 1 < len(criteria) {
+			currIndex = currIndex + 1
+			argument = criteria[currIndex]
+		}
+
+		if resolver.matchesCriteria(block, keyword, argument) == isNegated {
+			return false
+		}
+	}
+	return true
+}
+
+// Helper method that checks a single criteria of a Match line
+func (resolver *hostResolver) matchesCriteria(block *sshmkr_templates.ConfigEntry, keyword string, argument string) bool {
+	patterns := strings.Split(argument, ",")
+
+	switch keyword {
+		case "all", "final":
+			return true
+		case "canonical":
+			// Hostnames are never canonicalized here, so this pass is never the canonical one
+			return false
+		case "host":
+			return MatchesPatternList(resolver.getTargetHost(), patterns)
+		case "originalhost":
+			return MatchesPatternList(resolver.originalHost, patterns)
+		case "user":
+			remoteUser := resolver.getValue("User")
+			if remoteUser == "" {
+				remoteUser = getLocalUsername()
+			}
+			return MatchesPatternList(remoteUser, patterns)
+		case "localuser":
+			return MatchesPatternList(getLocalUsername(), patterns)
+		case "exec":
+			resolver.notes = append(resolver.notes, fmt.Sprintf("%s: exec commands are not run, so its exec criteria was treated as not matching", block.GetName()))
+			return false
+	}
+
+	resolver.notes = append(resolver.notes, fmt.Sprintf("%s: the %s criteria is not supported, so it was treated as not matching", block.GetName(), keyword))
+	return false
+}
+
+// Helper method that gets the hostname that ssh will connect to, based on the options found so far
+func (resolver *hostResolver) getTargetHost() string {
+	targetHost := resolver.getValue("Hostname")
+	if targetHost == "" {
+		return resolver.originalHost
+	}
+	return strings.Replace(targetHost, "%h", resolver.originalHost, -1)
+}
+
+// Checks if a name matches a list of ssh patterns
+// At least one pattern has to match, and none of the negated (!pattern) ones can match
+func MatchesPatternList(name string, patterns []string) bool {
+	hasMatch := false
+	for _, currPattern := range patterns {
+		if strings.HasPrefix(currPattern, "!") {
+			if MatchesPattern(name, currPattern[1:]) {
+				return false
+			}
+		} else if MatchesPattern(name, currPattern) {
+			hasMatch = true
+		}
+	}
+	return hasMatch
+}
+
+// Checks if a name matches a single ssh pattern, where * matches any amount of characters and ? matches one
+// Matching is not case sensitive, the same as ssh
+func MatchesPattern(name string, pattern string) bool {
+	name = strings.ToLower(name)
+	pattern = strings.ToLower(pattern)
+
+	// We keep track of the last * so we can go back to it when the rest of the pattern does not match
+	nameIndex, patternIndex := 0, 0
+	starIndex, starNameIndex := -1, 0
+	for nameIndex < len(name) {
+		if patternIndex < len(pattern) && (pattern[patternIndex] == '?' || pattern[patternIndex] == name[nameIndex]) {
+			nameIndex, patternIndex = nameIndex + 1, patternIndex + 1
+		} else if patternIndex < len(pattern) && pattern[patternIndex] == '*' {
+			starIndex, starNameIndex = patternIndex, nameIndex
+			patternIndex = patternIndex + 1
+		} else if starIndex != -1 {
+			starNameIndex = starNameIndex + 1
+			nameIndex, patternIndex = starNameIndex, starIndex + 1
+		} else {
+			return false
+		}
+	}
+
+	for patternIndex < len(pattern) && pattern[patternIndex] == '*' {
+		patternIndex = patternIndex + 1
+	}
+	return patternIndex == len(pattern)
+}
+
+// Helper method that describes where an option came from
+func describeOptionSource(option *resolvedOption) string {
+	if option.File == "" {
+		return option.Block
+	}
+	return fmt.Sprintf("%s:%d (%s)", option.File, option.Line, option.Block)
+}
+
+// Helper method that gets the name of the user running the program
+func getLocalUsername() string {
+	currUser, err := user.Current()
+	if err != nil {
+		return ""
+	}
+	return currUser.Username
+}
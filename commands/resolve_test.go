@@ -0,0 +1,96 @@
+package sshmkr_commands
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestMatchesPattern(t *testing.T) {
+	testCases := []struct {
+		name string
+		pattern string
+		want bool
+	}{
+		{"web", "web", true},
+		{"web", "WEB", true},
+		{"web", "db", false},
+		{"web", "we", false},
+		{"web", "*", true},
+		{"", "*", true},
+		{"web", "w*", true},
+		{"web", "*b", true},
+		{"web", "*e*", true},
+		{"web", "*x*", false},
+		{"web1.example.com", "web*.example.com", true},
+		{"web1.example.org", "web*.example.com", false},
+		{"web", "w?b", true},
+		{"web", "w?", false},
+		{"web", "???", true},
+		{"web", "????", false},
+		{"web1", "web?", true},
+		{"aab", "*ab", true},
+		{"abab", "*ab*ab", true},
+	}
+
+	for _, currCase := range testCases {
+		if got := MatchesPattern(currCase.name, currCase.pattern); got != currCase.want {
+			t.Errorf("MatchesPattern(%q, %q) = %v, want %v", currCase.name, currCase.pattern, got, currCase.want)
+		}
+	}
+}
+
+// Match criteria take their patterns as a comma separated list, i.e "Match host web*,!web2"
+func TestMatchesPatternList(t *testing.T) {
+	testCases := []struct {
+		name string
+		patterns string
+		want bool
+	}{
+		{"web", "web", true},
+		{"web", "db,web", true},
+		{"web", "db,cache", false},
+		{"web", "", false},
+		{"web", "*,!web", false},
+		{"db", "*,!web", true},
+		{"web", "!web,*", false},
+		{"web", "!db", false},
+		{"web1", "web*,!web2", true},
+		{"web2", "web*,!web2", false},
+		{"web2", "web*,!web?", false},
+		{"db.example.com", "*.example.com,!web.*", true},
+	}
+
+	for _, currCase := range testCases {
+		if got := MatchesPatternList(currCase.name, strings.Split(currCase.patterns, ",")); got != currCase.want {
+			t.Errorf("MatchesPatternList(%q, %q) = %v, want %v", currCase.name, currCase.patterns, got, currCase.want)
+		}
+	}
+}
+
+func TestMatchesBlockCriteria(t *testing.T) {
+	testCases := []struct {
+		block string
+		want bool
+	}{
+		{"Host web", true},
+		{"Host db web", true},
+		{"Host * !web", false},
+		{"Match host web", true},
+		{"Match host db,web*", true},
+		{"Match !host web", false},
+		{"Match all", true},
+		{"Match originalhost web host web", true},
+		{"Match originalhost db host web", false},
+		{"Match exec \"test -f /tmp/x\" host web", false},
+		{"Match !exec \"test -f /tmp/x\" host web", true},
+		{"Match host web !exec \"test -f /tmp/x\"", true},
+	}
+
+	for _, currCase := range testCases {
+		block := ParseTemplatedConfig(currCase.block + "\n\tUser me\n")[0]
+		resolver := &hostResolver{originalHost: "web"}
+		if got := resolver.matchesBlock(block); got != currCase.want {
+			t.Errorf("matchesBlock(%q) = %v, want %v", currCase.block, got, currCase.want)
+		}
+	}
+}
@@ -0,0 +1,133 @@
+package sshmkr_commands
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sshmkr/reader"
+	"sshmkr/templates"
+)
+
+// Sorts the hosts under each sub header alphabetically, and optionally the main/sub headers too
+// ssh uses the first value it finds, so Match blocks and Host blocks with wildcards are never moved,
+// and hosts are only sorted between them
+// Returns a warning for every group of headers that was left unsorted
+func SortConfig(sortHeaders bool, files *sshmkr_templates.ConfigFiles) []string {
+	warnings := []string{}
+	for _, currDoc := range files.Docs {
+		if sortHeaders {
+			if warning := sortSections(currDoc, 0, len(currDoc.Entries), sshmkr_templates.MainHeaderLine); warning != "" {
+				warnings = append(warnings, warning)
+			}
+			for currIndex := 0; currIndex < len(currDoc.Entries); currIndex = currIndex + 1 {
+				if currDoc.Entries[currIndex].Kind == sshmkr_templates.MainHeaderLine {
+					if warning := sortSections(currDoc, currIndex + 1, currDoc.FindMainSectionEnd(currIndex), sshmkr_templates.SubHeaderLine); warning != "" {
+						warnings = append(warnings, warning)
+					}
+				}
+			}
+		}
+
+		for currIndex := range currDoc.Entries {
+			if currDoc.Entries[currIndex].Kind == sshmkr_templates.SubHeaderLine {
+				sortHosts(currDoc, currIndex + 1, currDoc.FindSectionEnd(currIndex))
+			}
+		}
+	}
+	return warnings
+}
+
+// Helper method that sorts the hosts between the given indexes by name
+// The hosts are only moved within a run of hosts that only has blank lines between them,
+// so the comments, options and order sensitive blocks in between stay where they are
+func sortHosts(doc *sshmkr_templates.ConfigDocument, startIndex int, endIndex int) {
+	runIndexes := []int{}
+
+	sortRun := func() {
+		runHosts := []*sshmkr_templates.ConfigEntry{}
+		for _, currIndex := range runIndexes {
+			runHosts = append(runHosts, doc.Entries[currIndex])
+		}
+		sort.SliceStable(runHosts, func(i, j int) bool {
+			return strings.ToLower(runHosts[i].GetName()) < strings.ToLower(runHosts[j].GetName())
+		})
+		for runIndex, currIndex := range runIndexes {
+			doc.Entries[currIndex] = runHosts[runIndex]
+		}
+		runIndexes = []int{}
+	}
+
+	for currIndex := startIndex; currIndex < endIndex; currIndex = currIndex + 1 {
+		currEntry := doc.Entries[currIndex]
+		if currEntry.Kind == sshmkr_templates.BlankLine {
+			continue
+		} else if currEntry.IsBlock() && !isOrderSensitive(currEntry) {
+			runIndexes = append(runIndexes, currIndex)
+		} else {
+			sortRun()
+		}
+	}
+	sortRun()
+}
+
+// Helper method that sorts the main/sub header sections between the given indexes by name
+// Sections are not sorted if any of them has an order sensitive block in it, since moving it could change what ssh uses
+// Returns why the sections were not sorted, or an empty string if they were
+func sortSections(doc *sshmkr_templates.ConfigDocument, startIndex int, endIndex int, headerKind sshmkr_templates.LineKind) string {
+	sectionStarts := []int{}
+	for currIndex := startIndex; currIndex < endIndex; currIndex = currIndex + 1 {
+		if doc.Entries[currIndex].Kind == headerKind {
+			sectionStarts = append(sectionStarts, currIndex)
+		}
+	}
+	if len(sectionStarts) < 2 {
+		return ""
+	}
+
+	// Each section is taken without the blank lines at its end, which are placed back between the sections
+	sections := [][]*sshmkr_templates.ConfigEntry{}
+	trailingStart := endIndex
+	for sectionNum, sectionStart := range sectionStarts {
+		sectionEnd := endIndex
+		if sectionNum + 1 < len(sectionStarts) {
+			sectionEnd = sectionStarts[sectionNum+1]
+		}
+		contentEnd := sectionEnd
+		for contentEnd - 1 > sectionStart && doc.Entries[contentEnd-1].Kind == sshmkr_templates.BlankLine {
+			contentEnd = contentEnd - 1
+		}
+
+		for _, currEntry := range doc.Entries[sectionStart:contentEnd] {
+			if currEntry.IsBlock() && isOrderSensitive(currEntry) {
+				return fmt.Sprint("Skipped sorting the headers around ", sshmkr_reader.TrimHeaderIndicator(doc.Entries[sectionStart].Lines[0].Raw),
+					" since it has ", currEntry.GetName(), " in it, which ssh applies in order")
+			}
+		}
+		sections = append(sections, doc.Entries[sectionStart:contentEnd])
+		trailingStart = contentEnd
+	}
+
+	sort.SliceStable(sections, func(i, j int) bool {
+		return strings.ToLower(sshmkr_reader.TrimHeaderIndicator(sections[i][0].Lines[0].Raw)) < strings.ToLower(sshmkr_reader.TrimHeaderIndicator(sections[j][0].Lines[0].Raw))
+	})
+
+	sortedEntries := append([]*sshmkr_templates.ConfigEntry{}, doc.Entries[:sectionStarts[0]]...)
+	for sectionNum, currSection := range sections {
+		if sectionNum > 0 {
+			sortedEntries = append(sortedEntries, sshmkr_templates.NewBlankEntry())
+		}
+		sortedEntries = append(sortedEntries, currSection...)
+	}
+	sortedEntries = append(sortedEntries, doc.Entries[trailingStart:]...)
+	doc.Entries = sortedEntries
+	return ""
+}
+
+// Helper method that checks if moving a block could change the options that ssh uses
+// This is the case for Match blocks and Host blocks with wildcards, since they apply to more than one host
+func isOrderSensitive(entry *sshmkr_templates.ConfigEntry) bool {
+	if entry.IsCommented() {
+		return false
+	}
+	return entry.Kind == sshmkr_templates.MatchLine || hasWildcardPattern(entry)
+}
@@ -0,0 +1,43 @@
+package sshmkr_commands
+
+import (
+	"testing"
+	"sshmkr/reader"
+	"sshmkr/templates"
+)
+
+func TestSortConfigWarnings(t *testing.T) {
+	testCases := []struct {
+		name string
+		contents string
+		want string
+		wantWarnings int
+	}{
+		{
+			"sorts the headers",
+			"#### B\n## x\nHost b\n\tUser me\n\n#### A\n## y\nHost a\n\tUser me\n",
+			"#### A\n## y\nHost a\n\tUser me\n\n#### B\n## x\nHost b\n\tUser me\n",
+			0,
+		},
+		{
+			"skips headers with a wildcard host",
+			"#### B\n## x\nHost *\n\tUser me\n\n#### A\n## y\nHost a\n\tUser me\n",
+			"#### B\n## x\nHost *\n\tUser me\n\n#### A\n## y\nHost a\n\tUser me\n",
+			1,
+		},
+	}
+
+	for _, currCase := range testCases {
+		t.Run(currCase.name, func(t *testing.T) {
+			doc := sshmkr_reader.ParseConfigDocument([]byte(currCase.contents))
+			files := &sshmkr_templates.ConfigFiles{Docs: []*sshmkr_templates.ConfigDocument{doc}}
+			warnings := SortConfig(true, files)
+			if len(warnings) != currCase.wantWarnings {
+				t.Errorf("SortConfig() returned %d warnings %q, want %d", len(warnings), warnings, currCase.wantWarnings)
+			}
+			if got := doc.String(); got != currCase.want {
+				t.Errorf("SortConfig() left\n%q\nwant:\n%q", got, currCase.want)
+			}
+		})
+	}
+}
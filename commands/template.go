@@ -0,0 +1,134 @@
+package sshmkr_commands
+
+import (
+	"fmt"
+	"sshmkr/reader"
+	"sshmkr/templates"
+	"github.com/kevinburke/ssh_config"
+)
+
+// Prints out the name of every template that can be used by the add command
+func ListTemplates(templateFiles *sshmkr_templates.ConfigFiles) {
+	templateNames := sshmkr_reader.GetTemplateNames(templateFiles)
+	if len(templateNames) == 0 {
+		fmt.Println("No templates found in", templateFiles.GetMainDoc().Path, "!")
+		return
+	}
+	for _, currName := range templateNames {
+		fmt.Println(currName)
+	}
+}
+
+// Prints out a specific template, in the same output formats as the show command
+func ShowTemplate(templateName string, outputFormat string, templateFiles *sshmkr_templates.ConfigFiles) error {
+	doc, templateIndex, _, err := FindTemplate(templateName, templateFiles)
+	if err != nil {
+		return err
+	}
+	return printHostConfig(templateIndex, outputFormat, doc)
+}
+
+// Adds a new template with the given keys and default values to the end of the templates file
+func AddTemplate(templateName string, options []ssh_config.KV, templateFiles *sshmkr_templates.ConfigFiles) error {
+	if len(templateName) <= 0 {
+		return sshmkr_templates.NewConfigError(sshmkr_templates.ErrInvalidInput, "Source flag is empty! Please pass in a name for the new template!")
+	}
+
+	templateString := "Host " + templateName + "\n"
+	for _, currOption := range options {
+		templateString = templateString + fmt.Sprintf("\t%s %s\n", currOption.Key, currOption.Value)
+	}
+	return insertTemplate(templateString, templateFiles)
+}
+
+// Adds a new template that is a copy of an existing host config in the ssh_config
+// The template is named after the host (along with its aliases) unless a name is given
+// Returns the name of the new template
+func AddTemplateFromHost(hostname string, templateName string, configFiles *sshmkr_templates.ConfigFiles, templateFiles *sshmkr_templates.ConfigFiles) (string, error) {
+	_, _, host, err := FindSourceHost(hostname, false, "make a template from", configFiles)
+	if err != nil {
+		return "", err
+	}
+
+	blockKey := "Host"
+	if host.Kind == sshmkr_templates.MatchLine {
+		// Match blocks are named after their criteria, so they cannot be given a different name
+		if len(templateName) > 0 {
+			return "", sshmkr_templates.NewConfigError(sshmkr_templates.ErrInvalidInput, "Match blocks are named after their criteria, so the template cannot be given a name!")
+		}
+		blockKey = "Match"
+	}
+	if len(templateName) <= 0 {
+		templateName = host.GetHeaderLine().Value
+	}
+
+	templateString := blockKey + " " + templateName + "\n"
+	for _, currOption := range host.GetOptions() {
+		templateString = templateString + fmt.Sprintf("\t%s %s\n", currOption.Key, currOption.Value)
+	}
+	if err := insertTemplate(templateString, templateFiles); err != nil {
+		return "", err
+	}
+	return ParseTemplatedConfig(templateString)[0].GetName(), nil
+}
+
+// Edits an existing template with the values that were filled in from it, the same way the edit command does for hosts
+func EditTemplate(templateName string, templateString string, templateFiles *sshmkr_templates.ConfigFiles) error {
+	if _, _, _, err := FindTemplate(templateName, templateFiles); err != nil {
+		return err
+	}
+	return EditExisingConfig(templateName, templateString, templateFiles)
+}
+
+// Removes a specific template from the file it lives in, along with the comments attached to it
+func RemoveTemplate(templateName string, templateFiles *sshmkr_templates.ConfigFiles) error {
+	doc, templateIndex, _, err := FindTemplate(templateName, templateFiles)
+	if err != nil {
+		return err
+	}
+
+	RemoveEntryWithSpacing(templateIndex, doc)
+	return nil
+}
+
+// Finds the template that a template command was given
+// Returns the file the template is in, its index in that file and the template itself
+func FindTemplate(templateName string, templateFiles *sshmkr_templates.ConfigFiles) (*sshmkr_templates.ConfigDocument, int, *sshmkr_templates.ConfigEntry, error) {
+	if len(templateName) <= 0 {
+		return nil, -1, nil, sshmkr_templates.NewConfigError(sshmkr_templates.ErrInvalidInput, "Source flag is empty! Please pass in the name of a template!")
+	}
+
+	doc, templateIndex, template := templateFiles.FindBlock(templateName, false)
+	if templateIndex == -1 {
+		return nil, -1, nil, sshmkr_templates.NewConfigError(sshmkr_templates.ErrTemplateNotFound, "Cannot find template", templateName, "in config_templates file! Typo maybe?")
+	}
+	return doc, templateIndex, template, nil
+}
+
+// Helper method that places a new template at the end of the main templates file
+// A template cannot be added if any of its names is already used by another template
+func insertTemplate(templateString string, templateFiles *sshmkr_templates.ConfigFiles) error {
+	newEntries := ParseTemplatedConfig(templateString)
+	for _, currEntry := range newEntries {
+		templateNames := currEntry.GetPatterns()
+		if currEntry.Kind == sshmkr_templates.MatchLine {
+			templateNames = []string{currEntry.GetName()}
+		}
+		for _, currName := range templateNames {
+			if _, _, usedBy := templateFiles.FindBlock(currName, false); usedBy != nil {
+				return sshmkr_templates.NewConfigError(sshmkr_templates.ErrDuplicateHost, "The name", currName, "is already used by template", usedBy.GetName(), "!")
+			}
+		}
+	}
+
+	// Templates are placed after the last header of the file, if it has any, so they always end up at the very end
+	doc := templateFiles.GetMainDoc()
+	lastHeaderIndex := -1
+	for currIndex, currEntry := range doc.Entries {
+		if currEntry.Kind == sshmkr_templates.MainHeaderLine || currEntry.Kind == sshmkr_templates.SubHeaderLine {
+			lastHeaderIndex = currIndex
+		}
+	}
+	InsertIntoSection(lastHeaderIndex, newEntries, doc)
+	return nil
+}
@@ -0,0 +1,221 @@
+package sshmkr_config
+
+import (
+	"fmt"
+	"strings"
+	"sshmkr/commands"
+	"sshmkr/input"
+	"sshmkr/reader"
+	"sshmkr/templates"
+	"github.com/kevinburke/ssh_config"
+)
+
+// The kinds of errors that the operations return, which can be matched against with errors.Is
+var ErrHostNotFound = sshmkr_templates.ErrHostNotFound
+var ErrTemplateNotFound = sshmkr_templates.ErrTemplateNotFound
+var ErrHeaderNotFound = sshmkr_templates.ErrHeaderNotFound
+var ErrDuplicateHost = sshmkr_templates.ErrDuplicateHost
+var ErrInvalidInput = sshmkr_templates.ErrInvalidInput
+var ErrFileAccess = sshmkr_templates.ErrFileAccess
+var ErrConcurrentChange = sshmkr_templates.ErrConcurrentChange
+
+// Everything about a host config, along with the file and headers it is under
+type Host = sshmkr_templates.HostDetails
+
+// A single key/value of a host config
+type Option = sshmkr_templates.HostOption
+
+// Data struct that holds a ssh_config and every file it includes, along with the changes made to them since they were loaded
+// Nothing is written to disk until Save is called
+type Config struct {
+	Path string
+	Files *sshmkr_templates.ConfigFiles
+	BackupCount int		// Number of backups of each file that Save keeps
+	changes [][]string	// The changes that were made, written the same way as the command that would make them
+}
+
+// Reads in the ssh_config at the given location, along with every file it pulls in with Include
+func Load(configLoc string) (*Config, error) {
+	files, err := sshmkr_reader.ReadConfigFiles(configLoc)
+	if err != nil {
+		return nil, err
+	}
+	return &Config{Path: configLoc, Files: files, BackupCount: sshmkr_reader.DEFAULT_BACKUP_COUNT}, nil
+}
+
+// Waits for and holds the same lock that the sshmkr commands take while changing the ssh_config at the given location
+// The lock is held until the returned unlock function is called, and can be taken again while it is held (i.e by Save)
+func Lock(configLoc string) (func(), error) {
+	return sshmkr_reader.LockConfigFile(configLoc)
+}
+
+// Finds a host by any of its names, including hosts that are commented out
+func (config *Config) Find(hostname string) (Host, error) {
+	doc, hostIndex, _, err := sshmkr_commands.FindSourceHost(hostname, true, "find", config.Files)
+	if err != nil {
+		return Host{}, err
+	}
+	return sshmkr_commands.GetHostDetails(hostIndex, doc), nil
+}
+
+// Adds a new host with the given names and options under an existing "Main Header/Sub Header"
+func (config *Config) Add(headerPath string, patterns []string, options []Option) error {
+	if len(patterns) <= 0 {
+		return sshmkr_templates.NewConfigError(sshmkr_templates.ErrInvalidInput, "A new host needs at least one name!")
+	}
+	mainHeader, subHeader, err := sshmkr_commands.FindHeaderPath(headerPath, config.Files)
+	if err != nil {
+		return err
+	}
+
+	hostConfig := "Host " + strings.Join(patterns, " ") + "\n"
+	for _, currOption := range options {
+		hostConfig = hostConfig + fmt.Sprintf("\t%s %s\n", currOption.Key, currOption.Value)
+	}
+	if err := sshmkr_commands.AddTemplatedConfig(mainHeader, subHeader, hostConfig, config.Files); err != nil {
+		return err
+	}
+	config.changes = append(config.changes, []string{"add", "-header", headerPath, patterns[0]})
+	return nil
+}
+
+// Adds a new host from a template in the templates file next to the ssh_config, the same way the add command does
+// Keys that are not given in the values use the default of the template
+// Returns the name of the new host
+func (config *Config) AddFromTemplate(templateName string, headerPath string, values []Option) (string, error) {
+	templateFiles, err := sshmkr_reader.ReadTemplateFiles(config.Path, false)
+	if err != nil {
+		return "", err
+	}
+	template, err := sshmkr_reader.ReadAnnotatedTemplate(templateName, templateFiles)
+	if err != nil {
+		return "", err
+	}
+	mainHeader, subHeader, err := sshmkr_commands.FindHeaderPath(headerPath, config.Files)
+	if err != nil {
+		return "", err
+	}
+
+	inputOptions := getInputOptions(values)
+	inputOptions.HeaderPath = headerPath
+	hostConfig, hostName, err := sshmkr_input.InterpolateUserInput(template, inputOptions)
+	if err != nil {
+		return "", err
+	}
+	if err := sshmkr_commands.AddTemplatedConfig(mainHeader, subHeader, hostConfig, config.Files); err != nil {
+		return "", err
+	}
+	config.changes = append(config.changes, []string{"add", "-source", templateName, "-header", headerPath})
+	return hostName, nil
+}
+
+// Removes a host, along with the comments attached to it
+func (config *Config) Remove(hostname string) error {
+	if err := sshmkr_commands.RemoveHostConfig(hostname, config.Files); err != nil {
+		return err
+	}
+	config.changes = append(config.changes, []string{"delete", "-source", hostname})
+	return nil
+}
+
+// Comments out a host, or uncomments it if it is already commented out
+// Returns true if the host was commented out
+func (config *Config) Comment(hostname string) (bool, error) {
+	hasCommented, err := sshmkr_commands.CommentHostConfig(hostname, config.Files)
+	if err != nil {
+		return false, err
+	}
+	config.changes = append(config.changes, []string{"comment", "-source", hostname})
+	return hasCommented, nil
+}
+
+// Changes the given options of a host, adding the ones it does not have yet
+// The host is renamed if a value is given for the Host key
+func (config *Config) Edit(hostname string, values []Option) error {
+	template, err := sshmkr_reader.ReadSpecificTemplate(hostname, config.Files)
+	if err != nil {
+		return err
+	}
+	editedConfig, _, err := sshmkr_input.InterpolateUserInput(template, getInputOptions(values))
+	if err != nil {
+		return err
+	}
+	if err := sshmkr_commands.EditExisingConfig(hostname, editedConfig, config.Files); err != nil {
+		return err
+	}
+	config.changes = append(config.changes, []string{"edit", "-source", hostname})
+	return nil
+}
+
+// Moves a host, along with the comments attached to it, under an existing "Main Header/Sub Header"
+func (config *Config) Move(hostname string, headerPath string) error {
+	mainHeader, subHeader, err := sshmkr_commands.FindHeaderPath(headerPath, config.Files)
+	if err != nil {
+		return err
+	}
+	if err := sshmkr_commands.MoveHostConfig(hostname, mainHeader, subHeader, config.Files); err != nil {
+		return err
+	}
+	config.changes = append(config.changes, []string{"move", "-source", hostname, "-to", headerPath})
+	return nil
+}
+
+// Writes out every file that was changed since the config was loaded, and records the changes in the journal so they can be undone
+// The config is locked while it is written, and nothing is written if one of the files was changed by something else since it was loaded
+func (config *Config) Save() error {
+	changedDocs := config.Files.GetChangedDocs()
+	if len(changedDocs) == 0 {
+		return nil
+	}
+
+	unlock, err := sshmkr_reader.LockConfigFile(config.Path)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	if err := sshmkr_reader.CheckForOutsideChanges(changedDocs); err != nil {
+		return err
+	}
+	for _, currDoc := range changedDocs {
+		if err := sshmkr_reader.WriteToConfigFile(currDoc.Path, currDoc.String(), config.BackupCount); err != nil {
+			return err
+		}
+	}
+	if err := sshmkr_reader.RecordJournalEntry(config.Path, getJournalArgs(config.changes), changedDocs); err != nil {
+		return err
+	}
+
+	// The saved files are read in again, so the next Save only looks at the changes made after this one
+	files, err := sshmkr_reader.ReadConfigFiles(config.Path)
+	if err != nil {
+		return err
+	}
+	config.Files = files
+	config.changes = nil
+	return nil
+}
+
+// Helper method that turns the given values into options that fill in a template without prompting
+func getInputOptions(values []Option) sshmkr_templates.InputOptions {
+	options := sshmkr_templates.InputOptions{AcceptDefaults: true, NonInteractive: true}
+	for _, currValue := range values {
+		options.SetValues = append(options.SetValues, ssh_config.KV{Key: currValue.Key, Value: currValue.Value})
+	}
+	return options
+}
+
+// Helper method that joins the changes into the arguments recorded in the journal, with a ; between each change
+// Files that were changed directly have no recorded changes, so they are recorded as a save
+func getJournalArgs(changes [][]string) []string {
+	journalArgs := []string{}
+	if len(changes) == 0 {
+		return []string{"save"}
+	}
+	for currIndex, currChange := range changes {
+		if currIndex > 0 {
+			journalArgs = append(journalArgs, ";")
+		}
+		journalArgs = append(journalArgs, currChange...)
+	}
+	return journalArgs
+}
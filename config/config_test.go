@@ -0,0 +1,189 @@
+package sshmkr_config
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const testConfig = `#### Personal
+## Sites
+Host web
+	Hostname 10.0.0.5
+	User me
+
+#### Work
+## Servers
+Host old
+	Hostname 10.0.1.1
+
+Host build
+	Hostname 10.0.1.2
+`
+
+// Helper method that writes the test config into a new temporary directory, which is removed once the test is done
+func writeTestConfig(t *testing.T) string {
+	tempDir, err := ioutil.TempDir("", "sshmkr-config")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.RemoveAll(tempDir) })
+
+	configLoc := filepath.Join(tempDir, "config")
+	if err := ioutil.WriteFile(configLoc, []byte(testConfig), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return configLoc
+}
+
+func TestConfigChanges(t *testing.T) {
+	configLoc := writeTestConfig(t)
+	config, err := Load(configLoc)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	host, err := config.Find("web")
+	if err != nil {
+		t.Fatalf("Find() error = %v", err)
+	}
+	if host.MainHeader != "Personal" || host.SubHeader != "Sites" || len(host.Options) != 2 || host.Options[0].Value != "10.0.0.5" {
+		t.Errorf("Find() = %+v", host)
+	}
+
+	if err := config.Add("Work/Servers", []string{"db"}, []Option{{Key: "Hostname", Value: "10.0.1.3"}}); err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+	if err := config.Edit("web", []Option{{Key: "Hostname", Value: "10.0.0.9"}}); err != nil {
+		t.Fatalf("Edit() error = %v", err)
+	}
+	if err := config.Move("build", "Personal/Sites"); err != nil {
+		t.Fatalf("Move() error = %v", err)
+	}
+	if err := config.Remove("old"); err != nil {
+		t.Fatalf("Remove() error = %v", err)
+	}
+
+	// Nothing is written until Save is called
+	if contents, _ := ioutil.ReadFile(configLoc); string(contents) != testConfig {
+		t.Fatalf("the config was written before Save:\n%s", contents)
+	}
+	if err := config.Save(); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	want := `#### Personal
+## Sites
+Host web
+	Hostname 10.0.0.9
+	User me
+
+Host build
+	Hostname 10.0.1.2
+
+#### Work
+## Servers
+Host db
+	Hostname 10.0.1.3
+`
+	contents, err := ioutil.ReadFile(configLoc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(contents) != want {
+		t.Errorf("Save() wrote\n%q\nwant:\n%q", contents, want)
+	}
+
+	// The saved config is read in again, so it can keep being changed
+	if _, err := config.Find("db"); err != nil {
+		t.Errorf("Find() after Save() error = %v", err)
+	}
+}
+
+func TestConfigErrors(t *testing.T) {
+	config, err := Load(writeTestConfig(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		name string
+		run func() error
+		want error
+	}{
+		{"find a missing host", func() error { _, err := config.Find("missing"); return err }, ErrHostNotFound},
+		{"add a host that exists", func() error { return config.Add("Work/Servers", []string{"web"}, nil) }, ErrDuplicateHost},
+		{"add a host without a name", func() error { return config.Add("Work/Servers", nil, nil) }, ErrInvalidInput},
+		{"add under a missing header", func() error { return config.Add("Work/Nope", []string{"db"}, nil) }, ErrHeaderNotFound},
+		{"add from a missing templates file", func() error { _, err := config.AddFromTemplate("tpl", "Work/Servers", nil); return err }, ErrFileAccess},
+		{"remove a missing host", func() error { return config.Remove("missing") }, ErrHostNotFound},
+		{"edit a missing host", func() error { return config.Edit("missing", nil) }, ErrTemplateNotFound},
+		{"move under a missing header", func() error { return config.Move("web", "Nope/Nope") }, ErrHeaderNotFound},
+	}
+
+	for _, currCase := range testCases {
+		t.Run(currCase.name, func(t *testing.T) {
+			if err := currCase.run(); !errors.Is(err, currCase.want) {
+				t.Errorf("error = %v, want %v", err, currCase.want)
+			}
+		})
+	}
+}
+
+func TestConfigSaveOutsideChange(t *testing.T) {
+	configLoc := writeTestConfig(t)
+	config, err := Load(configLoc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := config.Remove("old"); err != nil {
+		t.Fatal(err)
+	}
+
+	outsideContents := testConfig + "\nHost added-by-an-editor\n"
+	if err := ioutil.WriteFile(configLoc, []byte(outsideContents), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := config.Save(); !errors.Is(err, ErrConcurrentChange) {
+		t.Fatalf("Save() error = %v, want %v", err, ErrConcurrentChange)
+	}
+	if contents, _ := ioutil.ReadFile(configLoc); string(contents) != outsideContents {
+		t.Errorf("Save() overwrote the outside change:\n%s", contents)
+	}
+}
+
+func TestConfigLock(t *testing.T) {
+	configLoc := writeTestConfig(t)
+
+	// The lock can be taken again while it is held, which Save does
+	unlock, err := Lock(configLoc)
+	if err != nil {
+		t.Fatalf("Lock() error = %v", err)
+	}
+	unlockAgain, err := Lock(configLoc)
+	if err != nil {
+		t.Fatalf("second Lock() error = %v", err)
+	}
+	config, err := Load(configLoc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := config.Remove("old"); err != nil {
+		t.Fatal(err)
+	}
+	if err := config.Save(); err != nil {
+		t.Fatalf("Save() while locked error = %v", err)
+	}
+	unlockAgain()
+	unlock()
+	unlock()
+
+	// Once every unlock function was called, the lock can be taken again
+	unlock, err = Lock(configLoc)
+	if err != nil {
+		t.Fatalf("Lock() after unlocking error = %v", err)
+	}
+	unlock()
+}
@@ -0,0 +1,158 @@
+package sshmkr_diff
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Constants
+const CONTEXT_LINES = 3
+
+// Data struct that holds a single line of a diff
+// Kind is ' ' for a line in both files, '-' for a removed line and '+' for an added line
+type diffLine struct {
+	Kind byte
+	Text string
+	BeforeNum int	// Line number in the before contents (0 if the line was added)
+	AfterNum int	// Line number in the after contents (0 if the line was removed)
+}
+
+// Creates a unified diff between two versions of a file
+// Returns an empty string if both versions are the same
+func UnifiedDiff(beforeName string, afterName string, before string, after string) string {
+	if before == after {
+		return ""
+	}
+
+	diffLines := diffByLine(strings.Split(before, "\n"), strings.Split(after, "\n"))
+	output := fmt.Sprintf("--- %s\n+++ %s\n", beforeName, afterName)
+
+	// Each hunk covers a group of changes, along with the lines around them
+	currIndex := 0
+	for currIndex < len(diffLines) {
+		if diffLines[currIndex].Kind == ' ' {
+			currIndex = currIndex + 1
+			continue
+		}
+
+		hunkStart := currIndex - CONTEXT_LINES
+		if hunkStart < 0 {
+			hunkStart = 0
+		}
+
+		// A hunk keeps going as long as the next change is close enough to share context lines
+		hunkEnd := currIndex
+		unchangedRun := 0
+		for hunkEnd < len(diffLines) && unchangedRun <= CONTEXT_LINES * 2 {
+			if diffLines[hunkEnd].Kind == ' ' {
+				unchangedRun = unchangedRun + 1
+			} else {
+				unchangedRun = 0
+			}
+			hunkEnd = hunkEnd + 1
+		}
+		hunkEnd = hunkEnd - unchangedRun + CONTEXT_LINES
+		if hunkEnd > len(diffLines) {
+			hunkEnd = len(diffLines)
+		}
+
+		output = output + formatHunk(diffLines[hunkStart:hunkEnd])
+		currIndex = hunkEnd
+	}
+
+	return output
+}
+
+// Helper method that formats a group of diff lines with its @@ range header
+func formatHunk(hunk []diffLine) string {
+	beforeStart, beforeCount := 0, 0
+	afterStart, afterCount := 0, 0
+	body := ""
+
+	for _, currLine := range hunk {
+		if currLine.Kind != '+' {
+			if beforeStart == 0 {
+				beforeStart = currLine.BeforeNum
+			}
+			beforeCount = beforeCount + 1
+		}
+		if currLine.Kind != '-' {
+			if afterStart == 0 {
+				afterStart = currLine.AfterNum
+			}
+			afterCount = afterCount + 1
+		}
+		body = body + string(currLine.Kind) + currLine.Text + "\n"
+	}
+
+	// A range with no lines points at the line before it
+	if beforeCount == 0 {
+		beforeStart = hunk[0].AfterNum - 1
+	}
+	if afterCount == 0 {
+		afterStart = hunk[0].BeforeNum - 1
+	}
+
+	return fmt.Sprintf("@@ -%d,%d +%d,%d @@\n%s", beforeStart, beforeCount, afterStart, afterCount, body)
+}
+
+// Helper method that lines up two lists of lines using their longest common subsequence
+func diffByLine(beforeLines []string, afterLines []string) []diffLine {
+	// The lines that are the same at the start and end do not need to be compared
+	prefixLen := 0
+	for prefixLen < len(beforeLines) && prefixLen < len(afterLines) && beforeLines[prefixLen] == afterLines[prefixLen] {
+		prefixLen = prefixLen + 1
+	}
+	suffixLen := 0
+	for suffixLen < len(beforeLines) - prefixLen && suffixLen < len(afterLines) - prefixLen &&
+		beforeLines[len(beforeLines)-1-suffixLen] == afterLines[len(afterLines)-1-suffixLen] {
+		suffixLen = suffixLen + 1
+	}
+
+	beforeMiddle := beforeLines[prefixLen:len(beforeLines)-suffixLen]
+	afterMiddle := afterLines[prefixLen:len(afterLines)-suffixLen]
+
+	// lcsTable[i][j] holds the length of the common subsequence of beforeMiddle[i:] and afterMiddle[j:]
+	lcsTable := make([][]int, len(beforeMiddle) + 1)
+	for currIndex := range lcsTable {
+		lcsTable[currIndex] = make([]int, len(afterMiddle) + 1)
+	}
+	for i := len(beforeMiddle) - 1; i >= 0; i = i - 1 {
+		for j := len(afterMiddle) - 1; j >= 0; j = j - 1 {
+			if beforeMiddle[i] == afterMiddle[j] {
+				lcsTable[i][j] = lcsTable[i+1][j+1] + 1
+			} else if lcsTable[i+1][j] >= lcsTable[i][j+1] {
+				lcsTable[i][j] = lcsTable[i+1][j]
+			} else {
+				lcsTable[i][j] = lcsTable[i][j+1]
+			}
+		}
+	}
+
+	diffLines := []diffLine{}
+	for currIndex := 0; currIndex < prefixLen; currIndex = currIndex + 1 {
+		diffLines = append(diffLines, diffLine{Kind: ' ', Text: beforeLines[currIndex], BeforeNum: currIndex + 1, AfterNum: currIndex + 1})
+	}
+
+	i, j := 0, 0
+	for i < len(beforeMiddle) || j < len(afterMiddle) {
+		if i < len(beforeMiddle) && j < len(afterMiddle) && beforeMiddle[i] == afterMiddle[j] {
+			diffLines = append(diffLines, diffLine{Kind: ' ', Text: beforeMiddle[i], BeforeNum: prefixLen + i + 1, AfterNum: prefixLen + j + 1})
+			i, j = i + 1, j + 1
+		} else if j >= len(afterMiddle) || (i < len(beforeMiddle) && lcsTable[i+1][j] >= lcsTable[i][j+1]) {
+			diffLines = append(diffLines, diffLine{Kind: '-', Text: beforeMiddle[i], BeforeNum: prefixLen + i + 1})
+			i = i + 1
+		} else {
+			diffLines = append(diffLines, diffLine{Kind: '+', Text: afterMiddle[j], AfterNum: prefixLen + j + 1})
+			j = j + 1
+		}
+	}
+
+	for currIndex := 0; currIndex < suffixLen; currIndex = currIndex + 1 {
+		beforeNum := len(beforeLines) - suffixLen + currIndex
+		afterNum := len(afterLines) - suffixLen + currIndex
+		diffLines = append(diffLines, diffLine{Kind: ' ', Text: beforeLines[beforeNum], BeforeNum: beforeNum + 1, AfterNum: afterNum + 1})
+	}
+
+	return diffLines
+}
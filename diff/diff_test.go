@@ -0,0 +1,71 @@
+package sshmkr_diff
+
+import (
+	"testing"
+)
+
+func TestUnifiedDiff(t *testing.T) {
+	testCases := []struct {
+		name string
+		before string
+		after string
+		want string
+	}{
+		{
+			"same contents",
+			"Host web\n\tUser me\n",
+			"Host web\n\tUser me\n",
+			"",
+		},
+		{
+			"changed line",
+			"Host web\n\tUser me\n",
+			"Host web\n\tUser you\n",
+			"--- a\n+++ b\n@@ -1,3 +1,3 @@\n Host web\n-\tUser me\n+\tUser you\n \n",
+		},
+		{
+			"added lines at the end",
+			"Host web\n\tUser me",
+			"Host web\n\tUser me\n\nHost db\n\tUser admin",
+			"--- a\n+++ b\n@@ -1,2 +1,5 @@\n Host web\n \tUser me\n+\n+Host db\n+\tUser admin\n",
+		},
+		{
+			"removed line",
+			"a\nb\nc",
+			"a\nc",
+			"--- a\n+++ b\n@@ -1,3 +1,2 @@\n a\n-b\n c\n",
+		},
+		{
+			"added to empty file",
+			"",
+			"Host web",
+			"--- a\n+++ b\n@@ -1,1 +1,1 @@\n-\n+Host web\n",
+		},
+		{
+			"only outside context is trimmed",
+			"1\n2\n3\n4\n5\n6\n7\n8\n9",
+			"1\n2\n3\n4\nfive\n6\n7\n8\n9",
+			"--- a\n+++ b\n@@ -2,7 +2,7 @@\n 2\n 3\n 4\n-5\n+five\n 6\n 7\n 8\n",
+		},
+		{
+			"changes far apart get their own hunks",
+			"1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n11\n12",
+			"one\n2\n3\n4\n5\n6\n7\n8\n9\n10\n11\ntwelve",
+			"--- a\n+++ b\n@@ -1,4 +1,4 @@\n-1\n+one\n 2\n 3\n 4\n@@ -9,4 +9,4 @@\n 9\n 10\n 11\n-12\n+twelve\n",
+		},
+		{
+			"changes close together share a hunk",
+			"1\n2\n3\n4\n5\n6\n7\n8",
+			"one\n2\n3\n4\n5\n6\n7\neight",
+			"--- a\n+++ b\n@@ -1,8 +1,8 @@\n-1\n+one\n 2\n 3\n 4\n 5\n 6\n 7\n-8\n+eight\n",
+		},
+	}
+
+	for _, currCase := range testCases {
+		t.Run(currCase.name, func(t *testing.T) {
+			if got := UnifiedDiff("a", "b", currCase.before, currCase.after); got != currCase.want {
+				t.Errorf("UnifiedDiff() =\n%s\nwant:\n%s", got, currCase.want)
+			}
+		})
+	}
+}
@@ -5,4 +5,5 @@ go 1.14
 require (
 	github.com/kevinburke/ssh_config v0.0.0-20190725054713-01f96b0aa0cd
 	github.com/mitchellh/go-homedir v1.1.0
+	gopkg.in/yaml.v3 v3.0.1
 )
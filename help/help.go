@@ -17,23 +17,42 @@ Adds in a new SSH configuration to the ssh_config file.
 This subcommand starts up an interactive add to the ssh_config file.
 The format of the new addition is based of on what is stored in
 ~/.ssh/config_templates (the default location). One can also omit that
-template flag to use the first template structure in that config.
+template flag to pick the template from a list of every template in that config.
 
 This command also allows for the config to be placed in specific areas of the config, which depend on specific headers. 
 These will be pre-determined during runtime and the user will be free to select them.
 
 This command will ignore templates that are commented out.
+The keys of a template can be annotated with "# @prompt text", "# @required", "# @choices a, b",
+"# @pattern regex" or "# @fixed" comments right above them, which change how they are prompted for.
+Template values can use {{.Key}} for the value of a key above them, {{.Header}} for the header of the
+new host, {{.Name}} for a variable that is prompted for (or passed in with -set), and ${NAME} for an
+environment variable.
+
+Values and placement can also be passed in as flags, which is useful for scripts.
+Anything that is not passed in is still prompted for, unless -non-interactive is given.
+With -answers, every host in the YAML file is added, using the defaults for the keys it does not have.
 
 Example:
   sshmkr add -source nameOfTemplate
+  sshmkr add -source nameOfTemplate -set Host=web -set Hostname=10.0.0.5 -header "Project 1/Instances" -yes
+  sshmkr add -source nameOfTemplate -answers answers.yaml
 
 Command Flags:
-	-source:	Tne name of the source template to use.
+	-source:	Tne name of the source template to use. (Prompts for one if not given)
+	-set:		Key=Value to use for a template key instead of prompting (can be repeated)
+	-header:	"Main Header/Sub Header" to place the new host under instead of prompting
+	-yes:		Use the default value of every key that was not set instead of prompting
+	-non-interactive:	Never prompt, and exit with an error if a value or header is missing
+	-answers:	YAML file with the header and values of one or more hosts to add
  
 Global Flags:
 	-help: 		Displays the help page for a specific command (or generally)
 	-version:	Prints out the current version of the application
 	-path:		Changes the default path to look for the ssh_config (default: ~/.ssh/config)
+	-backups:	Number of backups of the ssh_config to keep when it is changed (default: 5)
+	-dry-run:	Prints out a diff of the changes instead of writing them
+	-confirm:	Prints out a diff of the changes and asks before writing them
 `
 			case "delete":
 				helpText = `
@@ -52,6 +71,9 @@ Global Flags:
 	-help: 		Displays the help page for a specific command (or generally)
 	-version:	Prints out the current version of the application
 	-path:		Changes the default path to look for the ssh_config (default: ~/.ssh/config)
+	-backups:	Number of backups of the ssh_config to keep when it is changed (default: 5)
+	-dry-run:	Prints out a diff of the changes instead of writing them
+	-confirm:	Prints out a diff of the changes and asks before writing them
 `
 			case "copy":
 				helpText = `
@@ -66,14 +88,23 @@ config in the SSH config file, which is based off on headers.
 
 Example:
   sshmkr copy -source nameOfOriginalHost
+  sshmkr copy -source nameOfOriginalHost -set Host=newHost -header "Project 1/Instances" -yes
 
 Command Flags:
 	-source:	The name of the original SSH host to use as a template (REQUIRED)
+	-set:		Key=Value to use for a template key instead of prompting (can be repeated)
+	-header:	"Main Header/Sub Header" to place the new host under instead of prompting
+	-yes:		Use the default value of every key that was not set instead of prompting
+	-non-interactive:	Never prompt, and exit with an error if a value or header is missing
+	-answers:	YAML file with the header and values of one or more hosts to add
 
 Global Flags:
 	-help: 		Displays the help page for a specific command (or generally)
 	-version:	Prints out the current version of the application
 	-path:		Changes the default path to look for the ssh_config (default: ~/.ssh/config)
+	-backups:	Number of backups of the ssh_config to keep when it is changed (default: 5)
+	-dry-run:	Prints out a diff of the changes instead of writing them
+	-confirm:	Prints out a diff of the changes and asks before writing them
 `
 			case "show":
 				helpText = `
@@ -82,18 +113,29 @@ Shows a given SSH config to the screen.
 This command outputs the current configuration of a specific SSH host to the screen. 
 This is formatted as it appears in the config file as well as to stdout, making it easy to chain into other CLI commands.
 
-Note that if the specified host is commented out, this will ignore said hostname.
+Note that if the specified host is commented out, this will ignore said hostname,
+unless -commented is passed in.
+
+The host can also be printed out as JSON or YAML, which includes all of its patterns,
+its key/values (with their inline comments), the headers it lives under, its line range
+in the config and whether it is commented out.
 
 Example:
   sshmkr show -source nameOfHost
+  sshmkr show -source nameOfHost -output json
 
 Command Flags:
 	-source: The name of the SSH host to show (REQUIRED)
+	-output: The format to print the host in: text, json or yaml (default: text)
+	-commented: Also look at hosts that are commented out
 
 Global Flags:
 	-help: 		Displays the help page for a specific command (or generally)
 	-version:	Prints out the current version of the application
 	-path:		Changes the default path to look for the ssh_config (default: ~/.ssh/config)
+	-backups:	Number of backups of the ssh_config to keep when it is changed (default: 5)
+	-dry-run:	Prints out a diff of the changes instead of writing them
+	-confirm:	Prints out a diff of the changes and asks before writing them
 `
 			case "comment":
 				helpText = `
@@ -116,6 +158,9 @@ Global Flags:
 	-help: 		Displays the help page for a specific command (or generally)
 	-version:	Prints out the current version of the application
 	-path:		Changes the default path to look for the ssh_config (default: ~/.ssh/config)
+	-backups:	Number of backups of the ssh_config to keep when it is changed (default: 5)
+	-dry-run:	Prints out a diff of the changes instead of writing them
+	-confirm:	Prints out a diff of the changes and asks before writing them
 `
 			case "edit":
 				helpText = `
@@ -127,16 +172,396 @@ at which one can either accept them or type in a new value.
 Like the other commands, if the host config is commeted out, this command will ignore said
 hostname in its search.
 
+Keys that are passed in with -set but are not in the host config yet are added to it.
+
 Example:
   sshmkr edit -source nameOfHost
+  sshmkr edit -source nameOfHost -set Port=2222 -non-interactive
+  sshmkr edit -answers answers.yaml
 
 Command Flags:
 	-source:  The host to comment in/out
+	-set:		Key=Value to use for a key instead of prompting (can be repeated)
+	-yes:		Keep the current value of every key that was not set instead of prompting
+	-non-interactive:	Never prompt, and exit with an error if a value is missing
+	-answers:	YAML file with the values of one or more hosts to edit
+
+Global Flags:
+	-help: 		Displays the help page for a specific command (or generally)
+	-version:	Prints out the current version of the application
+	-path:		Changes the default path to look for the ssh_config (default: ~/.ssh/config)
+	-backups:	Number of backups of the ssh_config to keep when it is changed (default: 5)
+	-dry-run:	Prints out a diff of the changes instead of writing them
+	-confirm:	Prints out a diff of the changes and asks before writing them
+`
+			case "list":
+				helpText = `
+Lists every header in the SSH config and the hosts under them as a tree.
+
+This command walks the main headers and sub headers of the SSH config
+and prints out each host that lives under them. Hosts that are placed
+before any header are grouped under "(no header)".
+
+By default, hosts that are commented out are not listed.
+
+Example:
+  sshmkr list -header "Project 1/Instances" -summary
+
+Command Flags:
+	-header:	Only list the hosts under this main header, or "Main Header/Sub Header"
+	-commented:	Also list hosts that are commented out
+	-summary:	Show the Hostname, User and Port of each host
+
+Global Flags:
+	-help: 		Displays the help page for a specific command (or generally)
+	-version:	Prints out the current version of the application
+	-path:		Changes the default path to look for the ssh_config (default: ~/.ssh/config)
+	-backups:	Number of backups of the ssh_config to keep when it is changed (default: 5)
+	-dry-run:	Prints out a diff of the changes instead of writing them
+	-confirm:	Prints out a diff of the changes and asks before writing them
+`
+			case "history":
+				helpText = `
+Lists the changes that sshmkr made to the SSH config, newest first.
+
+Every command that changes the SSH config (add, delete, copy, comment, edit)
+records what it did in a journal that lives next to the config. The number in
+front of each change is how many undos it takes to revert it.
+
+Example:
+  sshmkr history -diff
+
+Command Flags:
+	-diff:	Show the changes that each command made to the SSH config
+
+Global Flags:
+	-help: 		Displays the help page for a specific command (or generally)
+	-version:	Prints out the current version of the application
+	-path:		Changes the default path to look for the ssh_config (default: ~/.ssh/config)
+	-backups:	Number of backups of the ssh_config to keep when it is changed (default: 5)
+	-dry-run:	Prints out a diff of the changes instead of writing them
+	-confirm:	Prints out a diff of the changes and asks before writing them
+`
+			case "undo":
+				helpText = `
+Reverts the last changes that sshmkr made to the SSH config.
+
+By default, only the last change is reverted. Passing in a number reverts
+that many of the last changes, newest first. The undo is refused if the 
+SSH config was changed outside of sshmkr since the change was made.
+With -dry-run or -confirm, the diff from the current SSH config to what
+it would be reverted to is printed out first.
+
+Example:
+  sshmkr undo 2
+  sshmkr -dry-run undo 2
+
+Global Flags:
+	-help: 		Displays the help page for a specific command (or generally)
+	-version:	Prints out the current version of the application
+	-path:		Changes the default path to look for the ssh_config (default: ~/.ssh/config)
+	-backups:	Number of backups of the ssh_config to keep when it is changed (default: 5)
+	-dry-run:	Prints out a diff of the changes instead of writing them
+	-confirm:	Prints out a diff of the changes and asks before writing them
+`
+			case "alias":
+				helpText = `
+Adds or removes an alias (an extra pattern on the Host line) of an existing SSH host.
+
+A host like "Host web web.internal 10.0.0.5" can be looked up by any of its aliases
+in every other command. An alias cannot be added if another host already uses it,
+and the last alias of a host cannot be removed.
+
+Example:
+  sshmkr alias add -source web -alias web.internal
+  sshmkr alias remove -source web -alias web.internal
+
+Command Flags:
+	-source:	The name (or any alias) of the host to change (REQUIRED)
+	-alias:		The alias to add or remove (REQUIRED)
+
+Global Flags:
+	-help: 		Displays the help page for a specific command (or generally)
+	-version:	Prints out the current version of the application
+	-path:		Changes the default path to look for the ssh_config (default: ~/.ssh/config)
+	-backups:	Number of backups of the ssh_config to keep when it is changed (default: 5)
+	-dry-run:	Prints out a diff of the changes instead of writing them
+	-confirm:	Prints out a diff of the changes and asks before writing them
+`
+			case "resolve":
+				helpText = `
+Displays the options that ssh would use when connecting to a hostname.
+
+This follows the same rules as ssh: every Host and Match block that matches the
+hostname is used (including the ones in included files), and the first value found
+for each option wins. Match exec criteria are never run and are treated as not matching.
+
+Example:
+  sshmkr resolve web
+  sshmkr resolve -explain web
+
+Command Flags:
+	-source:	The hostname to resolve (REQUIRED, can also be passed in after the flags)
+	-explain:	Shows the file, line and block each value came from, along with the values that were shadowed
+
+Global Flags:
+	-help: 		Displays the help page for a specific command (or generally)
+	-version:	Prints out the current version of the application
+	-path:		Changes the default path to look for the ssh_config (default: ~/.ssh/config)
+	-backups:	Number of backups of the ssh_config to keep when it is changed (default: 5)
+	-dry-run:	Prints out a diff of the changes instead of writing them
+	-confirm:	Prints out a diff of the changes and asks before writing them
+`
+			case "lint":
+				helpText = `
+Checks the ssh_config (and every file it includes) for problems.
+
+Every problem is printed with the file and line it is on, and the program exits
+with 1 if any problem was found, so it can be used in scripts and CI.
+
+Problems that are checked for:
+	duplicate-host:		A host name that is used by more than one block
+	shadowed-host:		Options of a host that are already set by an earlier wildcard Host block
+	unknown-keyword:	Keywords that ssh does not understand (along with a suggestion if it was misspelled)
+	host-outside-header:	Host/Match blocks that are not under any main header
+	orphan-sub-header:	Sub headers that are placed before any main header
+	empty-header:		Main/Sub headers that have nothing under them
+
+Example:
+  sshmkr lint
+  sshmkr lint -output json
+
+Command Flags:
+	-output:	Format to print the problems in [text, json, yaml] (default: text)
+
+Global Flags:
+	-help: 		Displays the help page for a specific command (or generally)
+	-version:	Prints out the current version of the application
+	-path:		Changes the default path to look for the ssh_config (default: ~/.ssh/config)
+	-backups:	Number of backups of the ssh_config to keep when it is changed (default: 5)
+	-dry-run:	Prints out a diff of the changes instead of writing them
+	-confirm:	Prints out a diff of the changes and asks before writing them
+`
+			case "header":
+				helpText = `
+Adds, renames, deletes or moves the main/sub headers of the ssh_config.
+
+Headers are given as a "Main Header" or "Main Header/Sub Header" path. Adding a sub
+header under a main header that does not exist yet creates both of them. A header
+that still has hosts under it is only deleted if -cascade is given, which deletes
+the hosts too. Main headers can only be moved around other main headers, and sub
+headers around other sub headers.
+
+Example:
+  sshmkr header add -header "Project 2/Instances"
+  sshmkr header rename -header "Project 2/Instances" -name "Servers"
+  sshmkr header delete -header "Project 2" -cascade
+  sshmkr header move -header "Project 2" -before "Personal"
+
+Command Flags:
+	-header:	The path of the header to change (REQUIRED)
+	-name:		The new name of the header (rename only)
+	-cascade:	Also delete the hosts that live under the header (delete only)
+	-before:	The path of the header to move the header in front of (move only)
+	-after:		The path of the header to move the header behind (move only)
+
+Global Flags:
+	-help: 		Displays the help page for a specific command (or generally)
+	-version:	Prints out the current version of the application
+	-path:		Changes the default path to look for the ssh_config (default: ~/.ssh/config)
+	-backups:	Number of backups of the ssh_config to keep when it is changed (default: 5)
+	-dry-run:	Prints out a diff of the changes instead of writing them
+	-confirm:	Prints out a diff of the changes and asks before writing them
+`
+			case "template":
+				helpText = `
+Lists, shows, adds, edits or deletes the templates in ~/.ssh/config_templates.
+
+Templates are given by name, either with -source or as the argument after the
+flags. Adding a template without -set prompts for its keys and default values,
+one "Key Value" line at a time, and creates the templates file if it does not
+exist yet. from-host makes a new template from a host in the ssh_config, named
+after the host unless -name is given.
+
+Example:
+  sshmkr template list
+  sshmkr template show nameOfTemplate
+  sshmkr template add nameOfTemplate -set Hostname=10.0.0.5 -set Port=22
+  sshmkr template edit nameOfTemplate -set Port=2222 -yes
+  sshmkr template delete nameOfTemplate
+  sshmkr template from-host nameOfHost -name nameOfTemplate
+
+Command Flags:
+	-source:	The name of the template to show, add, edit or delete (can also be passed in before the flags)
+	-name:		The name of the new template (from-host only)
+	-output:	Output format of the template: text, json or yaml (show only)
+	-set:		Key=Value of the template to use instead of prompting (add and edit only, can be repeated)
+	-yes:		Keep the value of every key that was not set instead of prompting (edit only)
+	-non-interactive:	Never prompt, and exit with an error if a value is missing
+
+Global Flags:
+	-help: 		Displays the help page for a specific command (or generally)
+	-version:	Prints out the current version of the application
+	-path:		Changes the default path to look for the ssh_config (default: ~/.ssh/config)
+	-backups:	Number of backups of the ssh_config to keep when it is changed (default: 5)
+	-dry-run:	Prints out a diff of the changes instead of writing them
+	-confirm:	Prints out a diff of the changes and asks before writing them
+`
+			case "move":
+				helpText = `
+Moves an existing SSH host config under another main/sub header.
+
+The whole host is moved as it is, along with the comments right above it, and is
+placed at the end of the new sub header. Hosts can also be moved into and out of
+files that are pulled in with Include. If no header is given, the header is selected
+the same way as in add, which also allows for a new header to be created.
+
+Example:
+  sshmkr move web -to "Project 2/Instances"
+  sshmkr move -source web
+
+Command Flags:
+	-source:	The name (or any alias) of the host to move (REQUIRED, can also be passed in before the flags)
+	-to:		"Main Header/Sub Header" to move the host under instead of prompting
+
+Global Flags:
+	-help: 		Displays the help page for a specific command (or generally)
+	-version:	Prints out the current version of the application
+	-path:		Changes the default path to look for the ssh_config (default: ~/.ssh/config)
+	-backups:	Number of backups of the ssh_config to keep when it is changed (default: 5)
+	-dry-run:	Prints out a diff of the changes instead of writing them
+	-confirm:	Prints out a diff of the changes and asks before writing them
+`
+			case "fmt":
+				helpText = `
+Rewrites the ssh_config (and every file it includes) into a consistent layout.
+
+Options are indented with a tab, keywords use the casing from the ssh_config man page,
+and there is a single space between each keyword and its value. Host blocks and headers
+are separated by a single blank line, and trailing whitespace is removed.
+
+Example:
+  sshmkr fmt
+  sshmkr fmt -check
+
+Command Flags:
+	-check:		Do not write anything, and exit with 1 if the ssh_config is not formatted
+	-diff:		Do not write anything, and print out the changes that would be made
+
+Global Flags:
+	-help: 		Displays the help page for a specific command (or generally)
+	-version:	Prints out the current version of the application
+	-path:		Changes the default path to look for the ssh_config (default: ~/.ssh/config)
+	-backups:	Number of backups of the ssh_config to keep when it is changed (default: 5)
+	-dry-run:	Prints out a diff of the changes instead of writing them
+	-confirm:	Prints out a diff of the changes and asks before writing them
+`
+			case "sort":
+				helpText = `
+Sorts the hosts under each sub header alphabetically.
+
+Since ssh uses the first value it finds for each option, Match blocks and Host blocks
+with wildcards are never moved, and hosts are only sorted between them.
+
+Example:
+  sshmkr sort
+  sshmkr sort -headers -diff
+
+Command Flags:
+	-headers:	Also sort the main headers, and the sub headers under each of them
+	-check:		Do not write anything, and exit with 1 if the ssh_config is not sorted
+	-diff:		Do not write anything, and print out the changes that would be made
+
+Global Flags:
+	-help: 		Displays the help page for a specific command (or generally)
+	-version:	Prints out the current version of the application
+	-path:		Changes the default path to look for the ssh_config (default: ~/.ssh/config)
+	-backups:	Number of backups of the ssh_config to keep when it is changed (default: 5)
+	-dry-run:	Prints out a diff of the changes instead of writing them
+	-confirm:	Prints out a diff of the changes and asks before writing them
+`
+			case "find":
+				helpText = `
+Searches every host for a query, and prints out the hosts that match along with their header.
+
+The names (aliases) of each host are searched, along with its Hostname, User,
+IdentityFile and ProxyJump values and its comments. The program exits with 1 if
+nothing matched.
+
+Search modes:
+	substring:	The query shows up anywhere in the value (default)
+	glob:		The whole value matches the query, where * matches anything and ? matches one character
+	regex:		The value matches the query as a regular expression
+	fuzzy:		Every letter of the query shows up in the value, in the same order
+
+Example:
+  sshmkr find 10.2.3.4
+  sshmkr find -mode glob "10.2.*"
+  sshmkr find id_ed25519
+
+Command Flags:
+	-mode:		How the query is matched [substring, glob, regex, fuzzy] (default: substring)
+	-commented:	Also search the hosts that are commented out
+
+Global Flags:
+	-help: 		Displays the help page for a specific command (or generally)
+	-version:	Prints out the current version of the application
+	-path:		Changes the default path to look for the ssh_config (default: ~/.ssh/config)
+	-backups:	Number of backups of the ssh_config to keep when it is changed (default: 5)
+	-dry-run:	Prints out a diff of the changes instead of writing them
+	-confirm:	Prints out a diff of the changes and asks before writing them
+`
+			case "completion":
+				helpText = `
+Prints out a completion script for bash, zsh or fish.
+
+The script completes the subcommands and their flags, and calls back into sshmkr
+to complete host names (-source), template names (add -source) and header paths
+(-header, -to, -before, -after). A -path passed in before the subcommand is used
+for these as well.
+
+Example:
+  source <(sshmkr completion bash)			# in ~/.bashrc
+  source <(sshmkr completion zsh)			# in ~/.zshrc
+  sshmkr completion fish > ~/.config/fish/completions/sshmkr.fish
 
 Global Flags:
 	-help: 		Displays the help page for a specific command (or generally)
 	-version:	Prints out the current version of the application
 	-path:		Changes the default path to look for the ssh_config (default: ~/.ssh/config)
+	-backups:	Number of backups of the ssh_config to keep when it is changed (default: 5)
+	-dry-run:	Prints out a diff of the changes instead of writing them
+	-confirm:	Prints out a diff of the changes and asks before writing them
+`
+			case "ui":
+				helpText = `
+Opens a full screen interface to browse and change the host configs.
+
+The header tree is shown on the left, and the options of the selected host
+on the right. Changes are made and saved the same way as the other commands,
+so they show up in the history and can be undone.
+
+Keys:
+	up/down, j/k:	Selects the previous/next host (PgUp/PgDn to jump a page)
+	/:		Filters the hosts by name, Hostname or User as you type (Esc to clear)
+	enter:		Shows the selected host
+	e:		Edits the selected host
+	c:		Copies the selected host into a new one
+	x:		Comments in/out the selected host
+	d:		Deletes the selected host, after asking to confirm
+	m:		Moves the selected host under another header
+	q:		Quits the interface
+
+Example:
+  sshmkr ui
+
+Global Flags:
+	-help: 		Displays the help page for a specific command (or generally)
+	-version:	Prints out the current version of the application
+	-path:		Changes the default path to look for the ssh_config (default: ~/.ssh/config)
+	-backups:	Number of backups of the ssh_config to keep when it is changed (default: 5)
+	-dry-run:	Prints out a diff of the changes instead of writing them
+	-confirm:	Prints out a diff of the changes and asks before writing them
 `
 		}
 		fmt.Println(helpText)
@@ -158,6 +583,9 @@ the following comments, which the program refers to as headers.
 	## Sub Header
 	# Comment
 
+Match blocks are handled the same way as hosts, and are referred to by their
+full criteria, i.e -source "Match user deploy host *.corp".
+
 This program also takes a special template file, (defaults to ~/.ssh/config_templates),
 that the program can use to create new host configurations from. The format of these are
 identical to how a normal host config would look like.
@@ -169,11 +597,28 @@ Commands:
 	copy:		Copies an existing host config and uses it as a template for a new config
 	show:		Displays a specified host config
 	edit:		Edits an existing SSH config
+	list:		Lists all of the headers and the hosts under them
+	alias:		Adds or removes aliases of an existing host config
+	resolve:	Displays the options that ssh would use for a hostname
+	lint:		Checks the ssh_config for problems
+	header:		Adds, renames, deletes or moves the main/sub headers
+	move:		Moves a host config under another header
+	template:	Lists, shows, adds, edits or deletes the templates
+	fmt:		Rewrites the ssh_config into a consistent layout
+	sort:		Sorts the hosts (and optionally headers) of the ssh_config
+	find:		Searches the hosts for a name, value or comment
+	ui:		Opens an interactive interface to browse and change the host configs
+	completion:	Prints out a shell completion script for bash, zsh or fish
+	history:	Lists the changes that sshmkr made to the ssh_config
+	undo:		Reverts the last changes that sshmkr made to the ssh_config
 
 Global Flags:
 	-help: 		Displays the help page for a specific command (or generally)
 	-version:	Prints out the current version of the application
 	-path:		Changes the default path to look for the ssh_config (default: ~/.ssh/config)
+	-backups:	Number of backups of the ssh_config to keep when it is changed (default: 5)
+	-dry-run:	Prints out a diff of the changes instead of writing them
+	-confirm:	Prints out a diff of the changes and asks before writing them
 `
 	fmt.Println(helpText)
 	os.Exit(0)
@@ -2,83 +2,405 @@ package sshmkr_input
 
 import (
 	"fmt"
+	"strconv"
 	"strings"
-	"os"
+	"sshmkr/reader"
 	"sshmkr/templates"
+	"github.com/kevinburke/ssh_config"
 )
 
+// Data struct that holds a value that was typed in, until it is written to the input history
+type historyValue struct {
+	historyLoc string
+	key string
+	value string
+}
+
+// Values that were typed in but not written to the input history yet, oldest first
+var pendingHistory []historyValue
+
 // Takes in a templated string and user input to return a filled host config
-func InterpolateUserInput(template sshmkr_templates.ConfigTemplate) (string, string){
-	hostName := ""
-	templateString := template.GetTemplatedString()
-	
-	fmt.Println("")
-	fmt.Println("~ Template ~")
+// Keys that were set in the options are not prompted for, and keys that the template does not have are added to the end
+// A key that was set more than once fills in each line of that key in order, and the values left over are added to the end
+// Every value is checked against the annotations of its key, and keys that are fixed by the template always keep their value
+// The {{.Name}} placeholders of a template are filled in with the keys above them, the header and the template's own variables
+func InterpolateUserInput(template sshmkr_templates.ConfigTemplate, options sshmkr_templates.InputOptions) (string, string, error) {
+	printedTitle := false
+	templateKeys := map[string]bool{}
+	history := readHistory(options.HistoryLoc)
+
+	// Prints the title once, right before the first prompt
+	printTitle := func() {
+		if !printedTitle {
+			fmt.Fprintln(Output, "")
+			fmt.Fprintln(Output, "~ Template ~")
+			printedTitle = true
+		}
+	}
+
+	for currIndex := 0; currIndex < template.GetNumKeyPairs(); currIndex = currIndex + 1 {
+		templateKeys[strings.ToLower(template.GetKeyPair(currIndex).Key)] = true
+	}
+	variables := getHeaderVariables(options.HeaderPath)
+
+	values := []string{}
+	keyUses := map[string]int{}		// Number of lines of each key that were filled in so far
 	for currIndex := 0; currIndex < template.GetNumKeyPairs(); currIndex = currIndex + 1 {
 		templateData := template.GetKeyPair(currIndex)
-		var userInput string
+		annotation := template.GetKeyAnnotation(currIndex)
 
-		fmt.Printf("Enter a value for %s [ default: %s ]: ", templateData.Key, templateData.Value)
-		fmt.Scanln(&userInput)
-		if userInput == "" {
-			templateString = strings.Replace(templateString, "%s", templateData.Value, 1)
-			if currIndex == 0 {
-				hostName = templateData.Value
+		if template.ExpandVariables {
+			// Variables that are not a key of the template are asked for right before the first key that uses them
+			for _, currName := range sshmkr_templates.GetVariableNames(templateData.Value) {
+				if _, hasValue := variables[strings.ToLower(currName)]; hasValue || templateKeys[strings.ToLower(currName)] {
+					continue
+				}
+				variableValue, err := readVariable(currName, options, history[strings.ToLower(currName)], printTitle)
+				if err != nil {
+					return "", "", err
+				}
+				variables[strings.ToLower(currName)] = variableValue
 			}
-		} else {
-			templateString = strings.Replace(templateString, "%s", userInput, 1)
-			if currIndex == 0 {
-				hostName = userInput
+
+			expandedValue, err := sshmkr_templates.ExpandVariables(templateData.Value, variables)
+			if err != nil {
+				return "", "", sshmkr_templates.NewConfigError(sshmkr_templates.ErrInvalidInput, "The default of", templateData.Key, "cannot be filled in!", err)
 			}
+			templateData.Value = expandedValue
+		}
+
+		userInput, wasSet := "", false
+		if setValues := options.GetSetValues(templateData.Key); keyUses[strings.ToLower(templateData.Key)] < len(setValues) {
+			userInput, wasSet = setValues[keyUses[strings.ToLower(templateData.Key)]], true
+		}
+		keyUses[strings.ToLower(templateData.Key)] = keyUses[strings.ToLower(templateData.Key)] + 1
+		if annotation.Fixed {
+			if wasSet && userInput != templateData.Value {
+				return "", "", sshmkr_templates.NewConfigError(sshmkr_templates.ErrInvalidInput, "The value of", templateData.Key, "is fixed by the template and cannot be changed!")
+			}
+			userInput = templateData.Value
+		} else if !wasSet {
+			if (options.AcceptDefaults || options.NonInteractive) && templateData.Value != "" {
+				userInput = templateData.Value
+			} else if options.NonInteractive {
+				return "", "", sshmkr_templates.NewConfigError(sshmkr_templates.ErrInvalidInput, fmt.Sprintf("No value was given for %s! Pass one in with -set %s=value", templateData.Key, templateData.Key))
+			} else {
+				printTitle()
+				var err error
+				userInput, err = promptForValue(templateData, annotation, history[strings.ToLower(templateData.Key)])
+				if err != nil {
+					return "", "", err
+				}
+				if userInput != templateData.Value {
+					recordHistory(options.HistoryLoc, templateData.Key, userInput)
+				}
+			}
+		}
+
+		if userInput == "" {
+			userInput = templateData.Value
+		}
+		if err := annotation.Validate(templateData.Key, userInput); err != nil {
+			return "", "", err
+		}
+		values = append(values, userInput)
+		variables[strings.ToLower(templateData.Key)] = userInput
+	}
+
+	templateString := template.Render(values)
+	setCounts := map[string]int{}
+	for _, currValue := range options.SetValues {
+		currKey := strings.ToLower(currValue.Key)
+		setCounts[currKey] = setCounts[currKey] + 1
+		_, isVariable := variables[currKey]
+		if templateKeys[currKey] && setCounts[currKey] <= keyUses[currKey] {
+			continue
+		} else if !templateKeys[currKey] && template.ExpandVariables && isVariable {
+			continue
+		} else if template.GetNumKeyPairs() > 0 && strings.EqualFold(currValue.Key, template.GetKeyPair(0).Key) {
+			return "", "", sshmkr_templates.NewConfigError(sshmkr_templates.ErrInvalidInput, currValue.Key, "can only be set once! Set every name at once instead, i.e -set \"" + currValue.Key + "=web web.example.com\"")
 		}
+		templateString = templateString + fmt.Sprintf("\t%s %s\n", currValue.Key, currValue.Value)
 	}
 
-	fmt.Println("")
-	return templateString, hostName
+	if printedTitle {
+		fmt.Fprintln(Output, "")
+	}
+
+	hostName := ""
+	if len(values) > 0 {
+		hostName = values[0]
+	}
+	return templateString, hostName, nil
+}
+
+// Helper method that gets the value of a variable that a template uses, which is not one of its keys
+// The value can be passed in with -set like a key, and is otherwise prompted for
+func readVariable(variableName string, options sshmkr_templates.InputOptions, variableHistory []string, printTitle func()) (string, error) {
+	if setValue, wasSet := options.GetSetValue(variableName); wasSet {
+		return setValue, nil
+	} else if options.NonInteractive {
+		return "", sshmkr_templates.NewConfigError(sshmkr_templates.ErrInvalidInput, fmt.Sprintf("No value was given for the variable %s! Pass one in with -set %s=value", variableName, variableName))
+	}
+
+	printTitle()
+	variableValue, err := ReadLine(fmt.Sprintf("Enter a value for %s: ", variableName), "", variableHistory)
+	if err != nil {
+		return "", err
+	}
+	variableValue = strings.TrimSpace(variableValue)
+	recordHistory(options.HistoryLoc, variableName, variableValue)
+	return variableValue, nil
+}
+
+// Helper method that gets the header variables of a "Main Header/Sub Header" path, which are empty if no path was given
+func getHeaderVariables(headerPath string) map[string]string {
+	mainName, subName := sshmkr_reader.SplitHeaderPath(headerPath)
+	fullPath := mainName
+	if subName != "" {
+		fullPath = mainName + "/" + subName
+	}
+	return map[string]string{
+		strings.ToLower(sshmkr_templates.HEADER_VARIABLE): fullPath,
+		strings.ToLower(sshmkr_templates.MAIN_HEADER_VARIABLE): mainName,
+		strings.ToLower(sshmkr_templates.SUB_HEADER_VARIABLE): subName,
+	}
+}
+
+// Helper method that asks for the value of a single template key until one is given that its annotations allow
+// The prompt text of the key is used if it has one, and its choices can be gone through with the up/down arrows
+func promptForValue(templateData ssh_config.KV, annotation sshmkr_templates.KeyAnnotation, keyHistory []string) (string, error) {
+	prompt := fmt.Sprintf("Enter a value for %s: ", templateData.Key)
+	if annotation.Prompt != "" {
+		prompt = fmt.Sprintf("%s (%s): ", annotation.Prompt, templateData.Key)
+	}
+	if len(annotation.Choices) > 0 {
+		fmt.Fprintf(Output, "Choices for %s: %s\n", templateData.Key, strings.Join(annotation.Choices, ", "))
+		keyHistory = annotation.Choices
+	}
+
+	for {
+		userInput, err := ReadLine(prompt, templateData.Value, keyHistory)
+		if err != nil {
+			return "", err
+		}
+		userInput = strings.TrimSpace(userInput)
+
+		checkedInput := userInput
+		if checkedInput == "" {
+			checkedInput = templateData.Value
+		}
+		err = annotation.Validate(templateData.Key, checkedInput)
+		if err == nil {
+			return userInput, nil
+		}
+		fmt.Fprintln(Output, err)
+	}
 }
 
 // Outputs all of the headers that the player can select and asks them to select a main/sub
+// The last choice of each list lets the player type in a new header, which is created when the host is added
+// If a header path was given in the options, that header is used without asking
 // Returns the headers that the player selected
-func SelectNewConfigLoc(headers []sshmkr_templates.HeaderBlock) (string, string) {
+func SelectNewConfigLoc(headers []sshmkr_templates.HeaderBlock, options sshmkr_templates.InputOptions) (string, string, error) {
+	if options.HeaderPath != "" {
+		return findHeaderPath(headers, options.HeaderPath)
+	} else if options.NonInteractive {
+		return "", "", sshmkr_templates.NewConfigError(sshmkr_templates.ErrInvalidInput, "No header was given for the new host! Pass one in with -header \"Main Header/Sub Header\"")
+	}
+
+	var err error
+
 	var mainHeaderIndex int
 	var subHeaderIndex int
 
 	var mainHeader string
 	var subHeader string
 
-	fmt.Println("~ Main Header Selection ~")
+	fmt.Fprintln(Output, "~ Main Header Selection ~")
 	for currIndex, currHeader := range headers {
-		commentStart := strings.Index(currHeader.GetMainHeader(), " ")
-		fmt.Printf("%d.) %s\n", currIndex + 1, currHeader.GetMainHeader()[commentStart:])
+		fmt.Fprintf(Output, "%d.)  %s\n", currIndex + 1, sshmkr_reader.TrimHeaderIndicator(currHeader.GetMainHeader()))
+	}
+	fmt.Fprintf(Output, "%d.)  (Create a new main header)\n", len(headers) + 1)
+	if mainHeaderIndex, err = readChoice("Select a main header: "); err != nil {
+		return "", "", err
 	}
-	fmt.Print("Select a main header: ")
-	fmt.Scanln(&mainHeaderIndex)
-	mainHeaderIndex = mainHeaderIndex - 1
 
-	if mainHeaderIndex < len(headers) && mainHeaderIndex >= 0 {
+	if mainHeaderIndex == len(headers) {
+		// A new main header has no sub headers yet, so a new sub header is always needed too
+		if mainHeader, err = readNewHeader("main", sshmkr_reader.MAIN_HEADER_IND); err != nil {
+			return "", "", err
+		}
+		if subHeader, err = readNewHeader("sub", sshmkr_reader.SUB_HEADER_IND); err != nil {
+			return "", "", err
+		}
+	} else if mainHeaderIndex < len(headers) && mainHeaderIndex >= 0 {
 		mainHeader = headers[mainHeaderIndex].GetMainHeader()
 
-		fmt.Println("")
-		fmt.Println("~ Sub Header Selection ~")
+		fmt.Fprintln(Output, "")
+		fmt.Fprintln(Output, "~ Sub Header Selection ~")
 		for currIndex, currSubHeader := range headers[mainHeaderIndex].GetSubHeaders() {
-			commentStart := strings.Index(currSubHeader, " ")
-			fmt.Printf("%d.) %s\n", currIndex + 1, currSubHeader[commentStart:])
+			fmt.Fprintf(Output, "%d.)  %s\n", currIndex + 1, sshmkr_reader.TrimHeaderIndicator(currSubHeader))
+		}
+		fmt.Fprintf(Output, "%d.)  (Create a new sub header)\n", len(headers[mainHeaderIndex].GetSubHeaders()) + 1)
+		if subHeaderIndex, err = readChoice("Select a sub header: "); err != nil {
+			return "", "", err
 		}
-		fmt.Print("Select a sub header: ")
-		fmt.Scanln(&subHeaderIndex)
-		subHeaderIndex = subHeaderIndex - 1
 
-		if subHeaderIndex <  len(headers[mainHeaderIndex].GetSubHeaders()) && subHeaderIndex >= 0 {
+		if subHeaderIndex == len(headers[mainHeaderIndex].GetSubHeaders()) {
+			if subHeader, err = readNewHeader("sub", sshmkr_reader.SUB_HEADER_IND); err != nil {
+				return "", "", err
+			}
+		} else if subHeaderIndex <  len(headers[mainHeaderIndex].GetSubHeaders()) && subHeaderIndex >= 0 {
 			subHeader = headers[mainHeaderIndex].GetSubHeaders()[subHeaderIndex]
 		} else {
-			fmt.Println("Invalid choice! Exiting program...")
-			os.Exit(1)
+			return "", "", sshmkr_templates.NewConfigError(sshmkr_templates.ErrInvalidInput, "Invalid choice!")
 		}
 
 	} else {
-		fmt.Println("Invalid Choice! Exiting program...")
-		os.Exit(1)
+		return "", "", sshmkr_templates.NewConfigError(sshmkr_templates.ErrInvalidInput, "Invalid Choice!")
+	}
+
+	return mainHeader, subHeader, nil
+}
+
+// Outputs all of the templates that the player can pick from and asks them to select one
+// Returns the name of the template that the player selected
+func SelectTemplate(templateNames []string, options sshmkr_templates.InputOptions) (string, error) {
+	if len(templateNames) == 0 {
+		return "", sshmkr_templates.NewConfigError(sshmkr_templates.ErrTemplateNotFound, "There are no templates in config_templates file! Add one with: sshmkr template add")
+	} else if options.NonInteractive {
+		return "", sshmkr_templates.NewConfigError(sshmkr_templates.ErrInvalidInput, "No template was given for the new host! Pass one in with -source nameOfTemplate")
+	}
+
+	fmt.Fprintln(Output, "~ Template Selection ~")
+	for currIndex, currName := range templateNames {
+		fmt.Fprintf(Output, "%d.) %s\n", currIndex + 1, currName)
+	}
+	templateIndex, err := readChoice("Select a template: ")
+	if err != nil {
+		return "", err
+	} else if templateIndex < 0 || templateIndex >= len(templateNames) {
+		return "", sshmkr_templates.NewConfigError(sshmkr_templates.ErrInvalidInput, "Invalid choice!")
+	}
+
+	fmt.Fprintln(Output, "")
+	return templateNames[templateIndex], nil
+}
+
+// Asks the player for the keys and values of a new template, one "Key Value" line at a time
+// An empty line ends the template
+func ReadTemplateOptions() ([]ssh_config.KV, error) {
+	templateOptions := []ssh_config.KV{}
+	fmt.Fprintln(Output, "~ New Template ~")
+	for {
+		optionLine, err := ReadLine("Enter a key and its default value (leave empty to finish): ", "", nil)
+		if err != nil {
+			return nil, err
+		}
+		optionLine = strings.TrimSpace(optionLine)
+		if optionLine == "" {
+			break
+		}
+
+		splitLine := strings.SplitN(optionLine, " ", 2)
+		if len(splitLine) == 1 {
+			splitLine = append(splitLine, "")
+		}
+		templateOptions = append(templateOptions, ssh_config.KV{Key: splitLine[0], Value: strings.TrimSpace(splitLine[1])})
+	}
+
+	fmt.Fprintln(Output, "")
+	return templateOptions, nil
+}
+
+// Helper method that asks the player for the name of a new main/sub header
+// Returns the new header line, which is the header indicator followed by the name
+func readNewHeader(headerKind string, headerInd string) (string, error) {
+	headerName, err := ReadLine(fmt.Sprintf("Enter the name of the new %s header: ", headerKind), "", nil)
+	if err != nil {
+		return "", err
+	}
+	headerName = strings.TrimSpace(headerName)
+	if headerName == "" || strings.Contains(headerName, "/") {
+		return "", sshmkr_templates.NewConfigError(sshmkr_templates.ErrInvalidInput, "Invalid header name!")
+	}
+	return headerInd + " " + headerName, nil
+}
+
+// Asks the user a yes/no question, where anything other than y or yes counts as a no
+func AskForConfirmation(question string) bool {
+	answer, err := ReadLine(fmt.Sprintf("%s [y/N]: ", question), "", nil)
+	if err != nil {
+		return false
+	}
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	return answer == "y" || answer == "yes"
+}
+
+// Helper method that reads the number of a choice from a numbered list
+// Returns the index of the choice, or -1 if what was typed in is not a number
+func readChoice(prompt string) (int, error) {
+	choice, err := ReadLine(prompt, "", nil)
+	if err != nil {
+		return -1, err
+	}
+	choiceNumber, err := strconv.Atoi(strings.TrimSpace(choice))
+	if err != nil {
+		return -1, nil
+	}
+	return choiceNumber - 1, nil
+}
+
+// Helper method that reads in the values that were typed in before, so the prompts can bring them back
+// Prompting still works without a history, so one that cannot be read is left empty
+func readHistory(historyLoc string) map[string][]string {
+	if historyLoc == "" {
+		return map[string][]string{}
+	}
+	history, err := sshmkr_reader.ReadInputHistory(historyLoc)
+	if err != nil {
+		return map[string][]string{}
+	}
+	return history
+}
+
+// Helper method that remembers a value that was typed in for a key, if a history file was given
+// The value is only kept in memory until SavePendingHistory is called, so nothing is remembered for changes that were never written
+func recordHistory(historyLoc string, key string, value string) {
+	if historyLoc != "" && value != "" {
+		pendingHistory = append(pendingHistory, historyValue{historyLoc: historyLoc, key: key, value: value})
 	}
+}
+
+// Writes the values that were typed in since the last save to the input history
+// This is called once the changes they were typed in for are written, and the history is only a convenience, so it cannot fail the save
+func SavePendingHistory() {
+	for _, currValue := range pendingHistory {
+		sshmkr_reader.RecordInputHistory(currValue.historyLoc, currValue.key, currValue.value)
+	}
+	pendingHistory = nil
+}
+
+// Forgets the values that were typed in since the last save, i.e when the prompts were aborted
+func DiscardPendingHistory() {
+	pendingHistory = nil
+}
+
+// Helper method that finds the main/sub header that matches a "Main Header/Sub Header" path
+func findHeaderPath(headers []sshmkr_templates.HeaderBlock, headerPath string) (string, string, error) {
+	mainName, subName := sshmkr_reader.SplitHeaderPath(headerPath)
 
-	return mainHeader, subHeader
-}
\ No newline at end of file
+	for _, currHeader := range headers {
+		if !strings.EqualFold(sshmkr_reader.TrimHeaderIndicator(currHeader.GetMainHeader()), mainName) {
+			continue
+		}
+		for _, currSubHeader := range currHeader.GetSubHeaders() {
+			if strings.EqualFold(sshmkr_reader.TrimHeaderIndicator(currSubHeader), subName) {
+				return currHeader.GetMainHeader(), currSubHeader, nil
+			}
+		}
+	}
+
+	return "", "", sshmkr_templates.NewConfigError(sshmkr_templates.ErrHeaderNotFound, "Cannot find header", headerPath, "in config. Typo maybe?")
+}
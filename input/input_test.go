@@ -0,0 +1,116 @@
+package sshmkr_input
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"sshmkr/reader"
+	"sshmkr/templates"
+	"github.com/kevinburke/ssh_config"
+)
+
+func TestInterpolateUserInputRepeatedValues(t *testing.T) {
+	testCases := []struct {
+		name string
+		template string
+		setValues []ssh_config.KV
+		want string
+		wantErr bool
+	}{
+		{
+			"one value per template line",
+			"Host tpl\n\tIdentityFile ~/.ssh/a\n\tIdentityFile ~/.ssh/b\n",
+			[]ssh_config.KV{{Key: "Host", Value: "web"}, {Key: "IdentityFile", Value: "~/.ssh/one"}, {Key: "IdentityFile", Value: "~/.ssh/two"}},
+			"\nHost web\n\tIdentityFile ~/.ssh/one\n\tIdentityFile ~/.ssh/two\n",
+			false,
+		},
+		{
+			"fewer values than template lines",
+			"Host tpl\n\tIdentityFile ~/.ssh/a\n\tIdentityFile ~/.ssh/b\n",
+			[]ssh_config.KV{{Key: "Host", Value: "web"}, {Key: "IdentityFile", Value: "~/.ssh/one"}},
+			"\nHost web\n\tIdentityFile ~/.ssh/one\n\tIdentityFile ~/.ssh/b\n",
+			false,
+		},
+		{
+			"more values than template lines",
+			"Host tpl\n\tIdentityFile ~/.ssh/a\n\tUser me\n",
+			[]ssh_config.KV{{Key: "Host", Value: "web"}, {Key: "identityfile", Value: "~/.ssh/one"}, {Key: "IdentityFile", Value: "~/.ssh/two"}, {Key: "Port", Value: "2222"}},
+			"\nHost web\n\tIdentityFile ~/.ssh/one\n\tUser me\n\tIdentityFile ~/.ssh/two\n\tPort 2222\n",
+			false,
+		},
+		{
+			"host set twice",
+			"Host tpl\n\tUser me\n",
+			[]ssh_config.KV{{Key: "Host", Value: "web"}, {Key: "Host", Value: "db"}},
+			"",
+			true,
+		},
+	}
+
+	Output = ioutil.Discard
+	for _, currCase := range testCases {
+		t.Run(currCase.name, func(t *testing.T) {
+			files := &sshmkr_templates.ConfigFiles{Docs: []*sshmkr_templates.ConfigDocument{sshmkr_reader.ParseConfigDocument([]byte(currCase.template))}}
+			template, err := sshmkr_reader.ReadSpecificTemplate("tpl", files)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			Input = strings.NewReader("")
+			got, _, err := InterpolateUserInput(template, sshmkr_templates.InputOptions{SetValues: currCase.setValues, NonInteractive: true})
+			if (err != nil) != currCase.wantErr {
+				t.Fatalf("InterpolateUserInput() error = %v, want error %v", err, currCase.wantErr)
+			}
+			if got != currCase.want {
+				t.Errorf("InterpolateUserInput() =\n%q\nwant:\n%q", got, currCase.want)
+			}
+		})
+	}
+}
+
+func TestInputHistoryOnlySavedAfterWrite(t *testing.T) {
+	tempDir, err := ioutil.TempDir("", "sshmkr-input")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.RemoveAll(tempDir) })
+	historyLoc := sshmkr_reader.GetInputHistoryLoc(filepath.Join(tempDir, "config"))
+
+	files := &sshmkr_templates.ConfigFiles{Docs: []*sshmkr_templates.ConfigDocument{sshmkr_reader.ParseConfigDocument([]byte("Host tpl\n\tUser me\n"))}}
+	template, err := sshmkr_reader.ReadSpecificTemplate("tpl", files)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	Output = ioutil.Discard
+	Input = strings.NewReader("web\nadmin\n")
+	if _, _, err = InterpolateUserInput(template, sshmkr_templates.InputOptions{HistoryLoc: historyLoc}); err != nil {
+		t.Fatal(err)
+	}
+	if history, _ := sshmkr_reader.ReadInputHistory(historyLoc); len(history) != 0 {
+		t.Fatalf("the history was written before the changes were saved: %v", history)
+	}
+
+	// Values from prompts that were never saved are dropped
+	DiscardPendingHistory()
+	SavePendingHistory()
+	if history, _ := sshmkr_reader.ReadInputHistory(historyLoc); len(history) != 0 {
+		t.Fatalf("discarded values were written to the history: %v", history)
+	}
+
+	Input = strings.NewReader("web\nadmin\n")
+	if _, _, err = InterpolateUserInput(template, sshmkr_templates.InputOptions{HistoryLoc: historyLoc}); err != nil {
+		t.Fatal(err)
+	}
+	SavePendingHistory()
+	history, err := sshmkr_reader.ReadInputHistory(historyLoc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(history["host"], []string{"web"}) || !reflect.DeepEqual(history["user"], []string{"admin"}) {
+		t.Errorf("SavePendingHistory() wrote %v, want web and admin", history)
+	}
+}
@@ -0,0 +1,240 @@
+package sshmkr_input
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+	"sshmkr/templates"
+)
+
+// Data struct that holds the state of a line while it is being edited
+type lineEditor struct {
+	prompt string
+	line []rune
+	cursor int			// Index in the line that the next typed character is placed at
+	history []string	// Values that were typed in before, oldest first, with the line being edited kept at the end
+	historyIndex int	// Index of the history value that is shown
+}
+
+// Where the prompts read their answers from and write their questions to
+// These can be swapped out to script or test the prompts, and lines are only editable when reading from a terminal on stdin
+var Input io.Reader = os.Stdin
+var Output io.Writer = os.Stdout
+
+// Keys that were read but not used by the last line, i.e the rest of a paste that had a newline in it
+var pendingKeys []byte
+
+// Reads a line from the user, with the default value already filled in so it can be edited in place
+// In a terminal the line can be edited like readline (arrows, home/end, ctrl-a/e/u/k/w), and up/down go through the history
+// Ctrl-C, or Ctrl-D on an empty line, aborts the prompt with ErrAborted, and so does the end of the input when it is piped in
+func ReadLine(prompt string, defaultValue string, history []string) (string, error) {
+	if Input != io.Reader(os.Stdin) || !IsTerminal() {
+		return readPipedLine(prompt, defaultValue)
+	}
+	restore, err := EnableRawMode()
+	if err != nil {
+		return readPipedLine(prompt, defaultValue)
+	}
+	defer restore()
+
+	editor := &lineEditor{prompt: prompt, line: []rune(defaultValue), history: append(append([]string{}, history...), defaultValue)}
+	editor.cursor = len(editor.line)
+	editor.historyIndex = len(editor.history) - 1
+	editor.render()
+
+	for {
+		key, err := readLineKey()
+		if err != nil {
+			fmt.Fprint(Output, "\r\n")
+			return "", newAbortedError()
+		}
+
+		switch key {
+			case "\r", "\n":
+				fmt.Fprint(Output, "\r\n")
+				return string(editor.line), nil
+			case "\x03":
+				fmt.Fprint(Output, "^C\r\n")
+				return "", newAbortedError()
+			case "\x04":
+				if len(editor.line) == 0 {
+					fmt.Fprint(Output, "\r\n")
+					return "", newAbortedError()
+				}
+				editor.deleteRunes(editor.cursor, editor.cursor + 1)
+			case "\x7f", "\b":
+				editor.deleteRunes(editor.cursor - 1, editor.cursor)
+			case "\x1b[3~":
+				editor.deleteRunes(editor.cursor, editor.cursor + 1)
+			case "\x1b[D", "\x02":
+				editor.moveCursor(editor.cursor - 1)
+			case "\x1b[C", "\x06":
+				editor.moveCursor(editor.cursor + 1)
+			case "\x1b[H", "\x1bOH", "\x1b[1~", "\x01":
+				editor.moveCursor(0)
+			case "\x1b[F", "\x1bOF", "\x1b[4~", "\x05":
+				editor.moveCursor(len(editor.line))
+			case "\x15":
+				editor.deleteRunes(0, editor.cursor)
+			case "\x0b":
+				editor.deleteRunes(editor.cursor, len(editor.line))
+			case "\x17":
+				editor.deleteRunes(editor.findWordStart(), editor.cursor)
+			case "\x1b[A", "\x10":
+				editor.showHistory(editor.historyIndex - 1)
+			case "\x1b[B", "\x0e":
+				editor.showHistory(editor.historyIndex + 1)
+			default:
+				keyRune, _ := utf8.DecodeRuneInString(key)
+				if strings.HasPrefix(key, "\x1b") || !unicode.IsPrint(keyRune) {
+					continue
+				}
+				editor.insertRunes([]rune(key))
+		}
+		editor.render()
+	}
+}
+
+// Checks if the prompts read from a terminal, rather than a file, a pipe or something else that was swapped in
+func IsTerminal() bool {
+	inputFile, isFile := Input.(*os.File)
+	if !isFile {
+		return false
+	}
+	inputInfo, err := inputFile.Stat()
+	return err == nil && inputInfo.Mode() & os.ModeCharDevice != 0
+}
+
+// Helper method that draws the prompt and the line over the current terminal line, and places the cursor
+func (editor *lineEditor) render() {
+	fmt.Fprint(Output, "\r" + editor.prompt + string(editor.line) + "\x1b[K")
+	if afterCursor := len(editor.line) - editor.cursor; afterCursor > 0 {
+		fmt.Fprintf(Output, "\x1b[%dD", afterCursor)
+	}
+}
+
+// Helper method that places the given characters at the cursor
+func (editor *lineEditor) insertRunes(newRunes []rune) {
+	newLine := append([]rune{}, editor.line[:editor.cursor]...)
+	newLine = append(newLine, newRunes...)
+	editor.line = append(newLine, editor.line[editor.cursor:]...)
+	editor.cursor = editor.cursor + len(newRunes)
+}
+
+// Helper method that removes the characters between the start and end index, leaving the cursor where they were
+func (editor *lineEditor) deleteRunes(start int, end int) {
+	if start < 0 || end > len(editor.line) || start >= end {
+		return
+	}
+	editor.line = append(editor.line[:start], editor.line[end:]...)
+	editor.cursor = start
+}
+
+// Helper method that moves the cursor, keeping it inside of the line
+func (editor *lineEditor) moveCursor(newCursor int) {
+	if newCursor >= 0 && newCursor <= len(editor.line) {
+		editor.cursor = newCursor
+	}
+}
+
+// Helper method that finds where the word before the cursor starts, skipping the spaces right before the cursor
+func (editor *lineEditor) findWordStart() int {
+	wordStart := editor.cursor
+	for wordStart > 0 && editor.line[wordStart-1] == ' ' {
+		wordStart = wordStart - 1
+	}
+	for wordStart > 0 && editor.line[wordStart-1] != ' ' {
+		wordStart = wordStart - 1
+	}
+	return wordStart
+}
+
+// Helper method that replaces the line with a value from the history
+// The line that is being edited is kept, so going back down to it brings it back
+func (editor *lineEditor) showHistory(newIndex int) {
+	if newIndex < 0 || newIndex >= len(editor.history) {
+		return
+	}
+	editor.history[editor.historyIndex] = string(editor.line)
+	editor.historyIndex = newIndex
+	editor.line = []rune(editor.history[newIndex])
+	editor.cursor = len(editor.line)
+}
+
+// Helper method that reads the next key press, which can be more than one byte for keys like the arrows
+// A read can hold more than one key (i.e when text is pasted), so the keys that are left are kept for the next call
+func readLineKey() (string, error) {
+	if len(pendingKeys) == 0 {
+		keyBuffer := make([]byte, 256)
+		readCount, err := Input.Read(keyBuffer)
+		if err != nil || readCount == 0 {
+			return "", newAbortedError()
+		}
+		pendingKeys = keyBuffer[:readCount]
+	}
+
+	keyLength := 1
+	if pendingKeys[0] == '\x1b' && len(pendingKeys) > 2 && (pendingKeys[1] == '[' || pendingKeys[1] == 'O') {
+		// Escape sequences end with the first letter or ~ after the [ or O
+		keyLength = 2
+		for keyLength < len(pendingKeys) {
+			keyLength = keyLength + 1
+			if lastByte := pendingKeys[keyLength-1]; lastByte == '~' || (lastByte >= 'A' && lastByte <= 'Z') || (lastByte >= 'a' && lastByte <= 'z') {
+				break
+			}
+		}
+	} else if pendingKeys[0] >= utf8.RuneSelf {
+		_, keyLength = utf8.DecodeRune(pendingKeys)
+	}
+
+	key := string(pendingKeys[:keyLength])
+	pendingKeys = pendingKeys[keyLength:]
+	return key, nil
+}
+
+// Helper method that reads a line when the input is not a terminal, so it cannot be edited
+// The default is shown in the prompt instead, and is used when nothing is typed in
+func readPipedLine(prompt string, defaultValue string) (string, error) {
+	if defaultValue != "" {
+		prompt = fmt.Sprintf("%s [ default: %s ]: ", strings.TrimSuffix(prompt, ": "), defaultValue)
+	}
+	fmt.Fprint(Output, prompt)
+
+	line, err := readInputLine()
+	if err != nil {
+		fmt.Fprintln(Output, "")
+		return "", err
+	} else if line == "" {
+		return defaultValue, nil
+	}
+	return line, nil
+}
+
+// Helper method that reads a whole line from standard input, spaces included
+// Stdin is read one byte at a time so nothing after the line is taken away from the next prompt
+// Returns ErrAborted if the input ends before anything is read
+func readInputLine() (string, error) {
+	line := []byte{}
+	currByte := make([]byte, 1)
+	for {
+		readCount, err := Input.Read(currByte)
+		if err != nil {
+			if len(line) == 0 {
+				return "", newAbortedError()
+			}
+			break
+		} else if readCount > 0 && currByte[0] == '\n' {
+			break
+		}
+		line = append(line, currByte[:readCount]...)
+	}
+	return strings.TrimRight(string(line), "\r"), nil
+}
+
+// Helper method that creates the error returned when the user stops a prompt
+func newAbortedError() error {
+	return sshmkr_templates.NewConfigError(sshmkr_templates.ErrAborted, "Aborted! Nothing was written to the ssh_config.")
+}
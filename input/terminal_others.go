@@ -0,0 +1,47 @@
+//go:build !windows
+// +build !windows
+
+package sshmkr_input
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// Puts the terminal into raw mode, where every key press is read right away and nothing is echoed back
+// Returns a function that puts the terminal back into the mode it was in before
+func EnableRawMode() (func(), error) {
+	origState, err := runStty("-g")
+	if err != nil {
+		return nil, fmt.Errorf("the terminal state cannot be read: %v", err)
+	}
+
+	if _, err := runStty("raw", "-echo"); err != nil {
+		return nil, fmt.Errorf("the terminal cannot be put into raw mode: %v", err)
+	}
+	return func() {
+		runStty(strings.TrimSpace(origState))
+	}, nil
+}
+
+// Gets the number of rows and columns of the terminal, falling back to 24x80 if it cannot be read
+func GetTerminalSize() (int, int) {
+	sizeOutput, err := runStty("size")
+	if err == nil {
+		var rows, cols int
+		if _, err := fmt.Sscan(sizeOutput, &rows, &cols); err == nil && rows > 0 && cols > 0 {
+			return rows, cols
+		}
+	}
+	return 24, 80
+}
+
+// Helper method that runs stty against the terminal that the program was started in
+func runStty(args ...string) (string, error) {
+	sttyCmd := exec.Command("stty", args...)
+	sttyCmd.Stdin = os.Stdin
+	output, err := sttyCmd.Output()
+	return string(output), err
+}
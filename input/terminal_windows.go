@@ -0,0 +1,15 @@
+package sshmkr_input
+
+import (
+	"fmt"
+)
+
+// Windows terminals cannot be put into raw mode with stty, so the interface and line editing are not supported there
+func EnableRawMode() (func(), error) {
+	return nil, fmt.Errorf("the terminal cannot be put into raw mode on windows")
+}
+
+// Windows terminals are never put into raw mode, so the default size is always returned
+func GetTerminalSize() (int, int) {
+	return 24, 80
+}
@@ -0,0 +1,120 @@
+package sshmkr_reader
+
+import (
+	"fmt"
+	"io/ioutil"
+	"strings"
+	"sshmkr/templates"
+	"github.com/kevinburke/ssh_config"
+	"gopkg.in/yaml.v3"
+)
+
+// Reads in an answers file, which holds the header and values of one host or a list of them
+// Every host is a mapping with the keys source, header and values, where values maps each ssh key to its value
+func ReadAnswersFile(answersLoc string) ([]sshmkr_templates.HostAnswers, error) {
+	answersContents, err := ioutil.ReadFile(answersLoc)
+	if err != nil {
+		return nil, sshmkr_templates.NewConfigError(sshmkr_templates.ErrFileAccess, "The answers file", answersLoc, "cannot be read!")
+	}
+
+	var parsedAnswers yaml.Node
+	if err := yaml.Unmarshal(answersContents, &parsedAnswers); err != nil {
+		return nil, invalidAnswersError(answersLoc, err.Error())
+	}
+
+	// A file with a single host does not need to be written as a list
+	answerList := []*yaml.Node{}
+	if len(parsedAnswers.Content) > 0 {
+		rootNode := resolveAlias(parsedAnswers.Content[0])
+		answerList = []*yaml.Node{rootNode}
+		if rootNode.Kind == yaml.SequenceNode {
+			answerList = rootNode.Content
+		}
+	}
+
+	allAnswers := []sshmkr_templates.HostAnswers{}
+	for currIndex, currAnswer := range answerList {
+		hostAnswers, err := parseHostAnswers(resolveAlias(currAnswer))
+		if err != nil {
+			return nil, invalidAnswersError(answersLoc, fmt.Sprintf("host %d: %s", currIndex + 1, err.Error()))
+		}
+		allAnswers = append(allAnswers, hostAnswers)
+	}
+	if len(allAnswers) == 0 {
+		return nil, invalidAnswersError(answersLoc, "it does not have any hosts")
+	}
+	return allAnswers, nil
+}
+
+// Helper method that reads the answers of a single host out of its mapping
+func parseHostAnswers(answerNode *yaml.Node) (sshmkr_templates.HostAnswers, error) {
+	hostAnswers := sshmkr_templates.HostAnswers{}
+	if answerNode.Kind != yaml.MappingNode {
+		return hostAnswers, fmt.Errorf("line %d: expected a mapping with source, header and values", answerNode.Line)
+	}
+
+	// The content of a mapping alternates between each key and its value
+	for currIndex := 0; currIndex + 1 < len(answerNode.Content); currIndex = currIndex + 2 {
+		keyNode := answerNode.Content[currIndex]
+		valueNode := resolveAlias(answerNode.Content[currIndex+1])
+
+		switch strings.ToLower(keyNode.Value) {
+			case "source":
+				if valueNode.Kind != yaml.ScalarNode {
+					return hostAnswers, fmt.Errorf("line %d: source must be a single value", valueNode.Line)
+				}
+				hostAnswers.Source = getScalarValue(valueNode)
+			case "header":
+				if valueNode.Kind != yaml.ScalarNode {
+					return hostAnswers, fmt.Errorf("line %d: header must be a \"Main Header/Sub Header\" path", valueNode.Line)
+				}
+				hostAnswers.HeaderPath = getScalarValue(valueNode)
+			case "values":
+				if valueNode.Kind != yaml.MappingNode {
+					return hostAnswers, fmt.Errorf("line %d: values must be a mapping of keys to their values", valueNode.Line)
+				}
+				for valueIndex := 0; valueIndex + 1 < len(valueNode.Content); valueIndex = valueIndex + 2 {
+					sshKey := valueNode.Content[valueIndex].Value
+					sshValue := resolveAlias(valueNode.Content[valueIndex+1])
+
+					// Keys that can be repeated (i.e IdentityFile) can be given a list of values
+					valueList := []*yaml.Node{sshValue}
+					if sshValue.Kind == yaml.SequenceNode {
+						valueList = sshValue.Content
+					}
+					for _, currListValue := range valueList {
+						currListValue = resolveAlias(currListValue)
+						if currListValue.Kind != yaml.ScalarNode {
+							return hostAnswers, fmt.Errorf("line %d: the value of %s must be a single value or a list of them", currListValue.Line, sshKey)
+						}
+						hostAnswers.Values = append(hostAnswers.Values, ssh_config.KV{Key: sshKey, Value: getScalarValue(currListValue)})
+					}
+				}
+			default:
+				return hostAnswers, fmt.Errorf("line %d: unknown key %s, expected source, header or values", keyNode.Line, keyNode.Value)
+		}
+	}
+	return hostAnswers, nil
+}
+
+// Helper method that follows an alias (i.e *name) to the node that it refers to
+func resolveAlias(node *yaml.Node) *yaml.Node {
+	for node.Kind == yaml.AliasNode && node.Alias != nil {
+		node = node.Alias
+	}
+	return node
+}
+
+// Helper method that gets the text of a scalar, where a null (i.e ~ or nothing at all) is an empty value
+// Every scalar is kept as it was typed, so values like 22 or yes are not turned into numbers or booleans
+func getScalarValue(node *yaml.Node) string {
+	if node.Tag == "!!null" {
+		return ""
+	}
+	return node.Value
+}
+
+// Helper method that creates the error for an answers file that cannot be used
+func invalidAnswersError(answersLoc string, reason string) error {
+	return sshmkr_templates.NewConfigError(sshmkr_templates.ErrInvalidInput, "The answers file", answersLoc, "is not valid!", reason)
+}
@@ -0,0 +1,102 @@
+package sshmkr_reader
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"sshmkr/templates"
+	"github.com/kevinburke/ssh_config"
+)
+
+func TestReadAnswersFile(t *testing.T) {
+	testCases := []struct {
+		name string
+		contents string
+		want []sshmkr_templates.HostAnswers
+	}{
+		{
+			"single host",
+			"source: web\nheader: Work/Servers\nvalues:\n  Host: web1\n  Port: 22\n  ForwardAgent: yes\n",
+			[]sshmkr_templates.HostAnswers{{Source: "web", HeaderPath: "Work/Servers", Values: []ssh_config.KV{{Key: "Host", Value: "web1"}, {Key: "Port", Value: "22"}, {Key: "ForwardAgent", Value: "yes"}}}},
+		},
+		{
+			"list of hosts with comments",
+			"# every web server\n- source: web # the template\n  values: {Host: web1}\n- source: web\n  values:\n    Host: web2\n",
+			[]sshmkr_templates.HostAnswers{{Source: "web", Values: []ssh_config.KV{{Key: "Host", Value: "web1"}}}, {Source: "web", Values: []ssh_config.KV{{Key: "Host", Value: "web2"}}}},
+		},
+		{
+			"quoted and empty values",
+			"values:\n  ProxyCommand: \"ssh -W %h:%p jump # host\"\n  User: 'it''s'\n  Empty:\n  Null: ~\n",
+			[]sshmkr_templates.HostAnswers{{Values: []ssh_config.KV{{Key: "ProxyCommand", Value: "ssh -W %h:%p jump # host"}, {Key: "User", Value: "it's"}, {Key: "Empty", Value: ""}, {Key: "Null", Value: ""}}}},
+		},
+		{
+			"flow and block lists of values",
+			"values:\n  IdentityFile: [~/.ssh/a, \"~/.ssh/b,c\"]\n  SendEnv:\n    - LANG\n    - LC_*\n",
+			[]sshmkr_templates.HostAnswers{{Values: []ssh_config.KV{{Key: "IdentityFile", Value: "~/.ssh/a"}, {Key: "IdentityFile", Value: "~/.ssh/b,c"}, {Key: "SendEnv", Value: "LANG"}, {Key: "SendEnv", Value: "LC_*"}}}},
+		},
+		{
+			"anchors and block scalars",
+			"- source: web\n  values: &shared\n    User: me\n    ProxyCommand: >-\n      ssh -W %h:%p\n      jump\n- source: db\n  values: *shared\n",
+			[]sshmkr_templates.HostAnswers{
+				{Source: "web", Values: []ssh_config.KV{{Key: "User", Value: "me"}, {Key: "ProxyCommand", Value: "ssh -W %h:%p jump"}}},
+				{Source: "db", Values: []ssh_config.KV{{Key: "User", Value: "me"}, {Key: "ProxyCommand", Value: "ssh -W %h:%p jump"}}},
+			},
+		},
+	}
+
+	for _, currCase := range testCases {
+		t.Run(currCase.name, func(t *testing.T) {
+			got, err := ReadAnswersFile(writeAnswersFile(t, currCase.contents))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, currCase.want) {
+				t.Errorf("ReadAnswersFile() = %+v, want %+v", got, currCase.want)
+			}
+		})
+	}
+}
+
+func TestReadAnswersFileErrors(t *testing.T) {
+	testCases := []struct {
+		name string
+		contents string
+	}{
+		{"empty file", ""},
+		{"only a comment", "# nothing here\n"},
+		{"tab indentation", "values:\n\tUser: me\n"},
+		{"bad indentation", "source: web\n  header: Work\n"},
+		{"not a mapping", "- web\n"},
+		{"unknown key", "source: web\nport: 22\n"},
+		{"source is a list", "source: [web, db]\n"},
+		{"values is a list", "values: [a, b]\n"},
+		{"nested value", "values:\n  User:\n    Name: me\n"},
+		{"unterminated quote", "values:\n  User: \"me\n"},
+	}
+
+	for _, currCase := range testCases {
+		t.Run(currCase.name, func(t *testing.T) {
+			if got, err := ReadAnswersFile(writeAnswersFile(t, currCase.contents)); err == nil {
+				t.Errorf("ReadAnswersFile() = %+v, want an error", got)
+			} else if !errors.Is(err, sshmkr_templates.ErrInvalidInput) {
+				t.Errorf("ReadAnswersFile() error = %v, want an invalid input error", err)
+			}
+		})
+	}
+}
+
+// Helper method that writes out an answers file for a test, which is removed once the test is done
+func writeAnswersFile(t *testing.T, contents string) string {
+	tempDir, err := ioutil.TempDir("", "sshmkr-answers")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.RemoveAll(tempDir) })
+
+	answersLoc := filepath.Join(tempDir, "answers.yaml")
+	writeTestFile(t, answersLoc, contents)
+	return answersLoc
+}
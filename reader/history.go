@@ -0,0 +1,65 @@
+package sshmkr_reader
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"sshmkr/templates"
+)
+
+// Constants
+const INPUT_HISTORY_SUFFIX = ".sshmkr-history"
+const INPUT_HISTORY_SIZE = 20
+
+// Gets the location of the file that holds the values that were typed into the prompts of a config file
+// Symlinks are followed the same way as the journal, so every link to the same config shares one history
+func GetInputHistoryLoc(configLoc string) string {
+	targetLoc, err := filepath.EvalSymlinks(configLoc)
+	if err != nil {
+		targetLoc = configLoc
+	}
+	return GetSideFileLoc(targetLoc, INPUT_HISTORY_SUFFIX)
+}
+
+// Reads in the values that were typed in for each template key, oldest first
+// Returns an empty history if nothing was typed in yet
+func ReadInputHistory(historyLoc string) (map[string][]string, error) {
+	history := map[string][]string{}
+
+	historyContents, err := readSideFile(historyLoc)
+	if os.IsNotExist(err) {
+		return history, nil
+	} else if err != nil || json.Unmarshal(historyContents, &history) != nil {
+		return nil, sshmkr_templates.NewConfigError(sshmkr_templates.ErrFileAccess, "The input history", historyLoc, "cannot be read!")
+	}
+	return history, nil
+}
+
+// Adds a value that was typed in for a key to the history and writes it out, only keeping the newest values of each key
+// A value that was typed in before is moved to the end instead of being added again
+func RecordInputHistory(historyLoc string, key string, value string) error {
+	history, err := ReadInputHistory(historyLoc)
+	if err != nil {
+		return err
+	}
+
+	key = strings.ToLower(key)
+	keyHistory := []string{}
+	for _, currValue := range history[key] {
+		if currValue != value {
+			keyHistory = append(keyHistory, currValue)
+		}
+	}
+	keyHistory = append(keyHistory, value)
+	if len(keyHistory) > INPUT_HISTORY_SIZE {
+		keyHistory = keyHistory[len(keyHistory)-INPUT_HISTORY_SIZE:]
+	}
+	history[key] = keyHistory
+
+	historyContents, err := json.MarshalIndent(history, "", "  ")
+	if err != nil {
+		return sshmkr_templates.NewConfigError(sshmkr_templates.ErrFileAccess, "The input history", historyLoc, "cannot be written!")
+	}
+	return writeSideFile(historyLoc, string(historyContents))
+}
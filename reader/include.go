@@ -0,0 +1,121 @@
+package sshmkr_reader
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"sshmkr/templates"
+)
+
+// Constants
+const MAX_INCLUDE_DEPTH = 16
+
+// Reads and parses the passed config file location, along with every file it pulls in with Include
+// Relative Include paths are looked up from the directory of the config file (~/.ssh by default), the same way ssh does
+func ReadConfigFiles(configLoc string) (*sshmkr_templates.ConfigFiles, error) {
+	files := &sshmkr_templates.ConfigFiles{Includes: map[*sshmkr_templates.ConfigLine][]*sshmkr_templates.ConfigDocument{}}
+	mainDoc, err := ReadConfigDocument(configLoc)
+	if err != nil {
+		return nil, err
+	}
+	files.Docs = append(files.Docs, mainDoc)
+
+	err = readIncludes(mainDoc, filepath.Dir(configLoc), files, map[string]bool{absolutePath(configLoc): true}, 1)
+	if err != nil {
+		return nil, err
+	}
+	return files, nil
+}
+
+// Helper method that reads every file that the given document includes, and the files that those include
+// The include stack keeps files from including themselves over and over
+func readIncludes(doc *sshmkr_templates.ConfigDocument, baseDir string, files *sshmkr_templates.ConfigFiles, includeStack map[string]bool, depth int) error {
+	if depth > MAX_INCLUDE_DEPTH {
+		return sshmkr_templates.NewConfigError(sshmkr_templates.ErrInvalidInput, "The config", doc.Path, "has too many nested Include directives!")
+	}
+
+	for _, currEntry := range doc.Entries {
+		for _, includeLine := range currEntry.GetIncludeLines() {
+			includeLocs, err := ExpandIncludePatterns(includeLine.Value, baseDir)
+			if err != nil {
+				return err
+			}
+			for _, includeLoc := range includeLocs {
+				if includeStack[includeLoc] {
+					continue
+				}
+
+				// A file that is included more than once is still only read once, so every change to it is kept
+				includedDoc := findReadDoc(files, includeLoc)
+				if includedDoc == nil {
+					includedDoc, err = ReadConfigDocument(includeLoc)
+					if err != nil {
+						return err
+					}
+					files.Docs = append(files.Docs, includedDoc)
+
+					includeStack[includeLoc] = true
+					err = readIncludes(includedDoc, baseDir, files, includeStack, depth + 1)
+					delete(includeStack, includeLoc)
+					if err != nil {
+						return err
+					}
+				}
+				files.Includes[includeLine] = append(files.Includes[includeLine], includedDoc)
+			}
+		}
+	}
+	return nil
+}
+
+// Turns the value of an Include line into the list of files that it matches
+// Each space separated pattern can use ~ and globs, and patterns that match nothing are skipped like ssh does
+func ExpandIncludePatterns(includeValue string, baseDir string) ([]string, error) {
+	includeLocs := []string{}
+
+	for _, currPattern := range strings.Fields(includeValue) {
+		currPattern = strings.Trim(currPattern, "\"")
+		if strings.HasPrefix(currPattern, "~/") {
+			homeDir, err := os.UserHomeDir()
+			if err == nil {
+				currPattern = filepath.Join(homeDir, currPattern[2:])
+			}
+		} else if !filepath.IsAbs(currPattern) {
+			currPattern = filepath.Join(baseDir, currPattern)
+		}
+
+		matches, err := filepath.Glob(currPattern)
+		if err != nil {
+			return nil, sshmkr_templates.NewConfigError(sshmkr_templates.ErrInvalidInput, "The Include pattern", currPattern, "is not valid!")
+		}
+		for _, currMatch := range matches {
+			// Like ssh, a * does not match hidden files unless the pattern itself starts with a .
+			if strings.HasPrefix(filepath.Base(currMatch), ".") && !strings.HasPrefix(filepath.Base(currPattern), ".") {
+				continue
+			}
+			if info, err := os.Stat(currMatch); err == nil && !info.IsDir() {
+				includeLocs = append(includeLocs, absolutePath(currMatch))
+			}
+		}
+	}
+	return includeLocs, nil
+}
+
+// Helper method that finds a document that was already read in
+func findReadDoc(files *sshmkr_templates.ConfigFiles, configLoc string) *sshmkr_templates.ConfigDocument {
+	for _, currDoc := range files.Docs {
+		if absolutePath(currDoc.Path) == configLoc {
+			return currDoc
+		}
+	}
+	return nil
+}
+
+// Helper method that turns a path into an absolute one, falling back to the path as is
+func absolutePath(configLoc string) string {
+	absLoc, err := filepath.Abs(configLoc)
+	if err != nil {
+		return configLoc
+	}
+	return absLoc
+}
@@ -0,0 +1,89 @@
+package sshmkr_reader
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+	"sshmkr/diff"
+	"sshmkr/templates"
+)
+
+// Constants
+const JOURNAL_SUFFIX = ".sshmkr-journal"
+const JOURNAL_SIZE = 50
+
+// Gets the location of the journal that belongs to a config file
+// Symlinks are followed so that every link to the same config shares one journal
+func GetJournalLoc(configLoc string) string {
+	targetLoc, err := filepath.EvalSymlinks(configLoc)
+	if err != nil {
+		targetLoc = configLoc
+	}
+	return GetSideFileLoc(targetLoc, JOURNAL_SUFFIX)
+}
+
+// Reads in every entry of the journal that belongs to a config file, oldest first
+// Returns an empty list if no changes were recorded yet
+func ReadJournal(configLoc string) ([]sshmkr_templates.JournalEntry, error) {
+	entries := []sshmkr_templates.JournalEntry{}
+
+	journalContents, err := readSideFile(GetJournalLoc(configLoc))
+	if os.IsNotExist(err) {
+		return entries, nil
+	} else if err != nil || json.Unmarshal(journalContents, &entries) != nil {
+		return nil, sshmkr_templates.NewConfigError(sshmkr_templates.ErrFileAccess, "The journal", GetJournalLoc(configLoc), "cannot be read!")
+	}
+	return entries, nil
+}
+
+// Writes out the entries of a journal, only keeping the newest ones
+func WriteJournal(configLoc string, entries []sshmkr_templates.JournalEntry) error {
+	if len(entries) > JOURNAL_SIZE {
+		entries = entries[len(entries)-JOURNAL_SIZE:]
+	}
+
+	journalContents, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return sshmkr_templates.NewConfigError(sshmkr_templates.ErrFileAccess, "The journal", GetJournalLoc(configLoc), "cannot be written!")
+	}
+	// The journal holds full copies of the config, so it is kept private no matter what
+	return writeSideFile(GetJournalLoc(configLoc), string(journalContents))
+}
+
+// Records the changes that a command made to the config and its included files in the config's journal
+// Nothing is recorded if no files were changed
+func RecordJournalEntry(configLoc string, cmdArgs []string, changedDocs []*sshmkr_templates.ConfigDocument) error {
+	if len(changedDocs) == 0 {
+		return nil
+	}
+
+	newEntry := sshmkr_templates.JournalEntry{
+		Command: cmdArgs[0],
+		Args: cmdArgs[1:],
+		Timestamp: time.Now().Format(time.RFC3339),
+	}
+	for _, currDoc := range changedDocs {
+		newContents := currDoc.String()
+		newEntry.Files = append(newEntry.Files, sshmkr_templates.JournalFileChange{
+			Path: currDoc.Path,
+			BeforeHash: HashContents(currDoc.OrigContents),
+			AfterHash: HashContents(newContents),
+			Diff: sshmkr_diff.UnifiedDiff(currDoc.Path, currDoc.Path, currDoc.OrigContents, newContents),
+			Before: currDoc.OrigContents,
+		})
+	}
+	entries, err := ReadJournal(configLoc)
+	if err != nil {
+		return err
+	}
+	return WriteJournal(configLoc, append(entries, newEntry))
+}
+
+// Creates a hash of the passed in file contents, used to tell if a file changed
+func HashContents(contents string) string {
+	hash := sha256.Sum256([]byte(contents))
+	return hex.EncodeToString(hash[:])
+}
@@ -0,0 +1,43 @@
+package sshmkr_reader
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"sshmkr/templates"
+)
+
+func TestJournalReadsLegacyLocation(t *testing.T) {
+	tempDir, err := ioutil.TempDir("", "sshmkr-writer")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tempDir)
+	configLoc := filepath.Join(tempDir, "config")
+	writeTestFile(t, configLoc, "")
+
+	legacyLoc := filepath.Join(tempDir, "config.sshmkr-journal")
+	writeTestFile(t, legacyLoc, `[{"command": "add", "args": [], "timestamp": "2020-01-01T00:00:00Z", "files": []}]`)
+
+	entries, err := ReadJournal(configLoc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 || entries[0].Command != "add" {
+		t.Fatalf("ReadJournal() = %+v, want the entry from the legacy journal", entries)
+	}
+
+	if err := WriteJournal(configLoc, append(entries, sshmkr_templates.JournalEntry{Command: "delete"})); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(legacyLoc); !os.IsNotExist(err) {
+		t.Errorf("legacy journal should be removed once the journal is written")
+	}
+	if GetJournalLoc(configLoc) != filepath.Join(tempDir, ".config.sshmkr-journal") {
+		t.Errorf("GetJournalLoc() = %s, want a hidden file next to the config", GetJournalLoc(configLoc))
+	}
+	if entries, err = ReadJournal(configLoc); err != nil || len(entries) != 2 {
+		t.Errorf("ReadJournal() after writing = %+v, %v, want both entries", entries, err)
+	}
+}
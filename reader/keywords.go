@@ -0,0 +1,125 @@
+package sshmkr_reader
+
+import (
+	"strings"
+)
+
+// All of the keywords that the OpenSSH client understands in a ssh config
+// Each keyword is looked up by its lowercase name, and maps to the casing used in the ssh_config man page
+var knownKeywords = map[string]string{
+	"addkeystoagent": "AddKeysToAgent", "addressfamily": "AddressFamily", "batchmode": "BatchMode",
+	"bindaddress": "BindAddress", "bindinterface": "BindInterface",
+	"canonicaldomains": "CanonicalDomains", "canonicalizefallbacklocal": "CanonicalizeFallbackLocal",
+	"canonicalizehostname": "CanonicalizeHostname", "canonicalizemaxdots": "CanonicalizeMaxDots",
+	"canonicalizepermittedcnames": "CanonicalizePermittedCNAMEs",
+	"casignaturealgorithms": "CASignatureAlgorithms", "certificatefile": "CertificateFile",
+	"challengeresponseauthentication": "ChallengeResponseAuthentication",
+	"checkhostip": "CheckHostIP", "ciphers": "Ciphers", "clearallforwardings": "ClearAllForwardings",
+	"compression": "Compression", "connectionattempts": "ConnectionAttempts",
+	"connecttimeout": "ConnectTimeout", "controlmaster": "ControlMaster",
+	"controlpath": "ControlPath", "controlpersist": "ControlPersist",
+	"dynamicforward": "DynamicForward", "enableescapecommandline": "EnableEscapeCommandline",
+	"enablesshkeysign": "EnableSSHKeysign", "escapechar": "EscapeChar",
+	"exitonforwardfailure": "ExitOnForwardFailure", "fingerprinthash": "FingerprintHash",
+	"forkafterauthentication": "ForkAfterAuthentication", "forwardagent": "ForwardAgent",
+	"forwardx11": "ForwardX11", "forwardx11timeout": "ForwardX11Timeout",
+	"forwardx11trusted": "ForwardX11Trusted", "gatewayports": "GatewayPorts",
+	"globalknownhostsfile": "GlobalKnownHostsFile", "gssapiauthentication": "GSSAPIAuthentication",
+	"gssapidelegatecredentials": "GSSAPIDelegateCredentials", "hashknownhosts": "HashKnownHosts",
+	"host": "Host", "hostbasedacceptedalgorithms": "HostbasedAcceptedAlgorithms",
+	"hostbasedauthentication": "HostbasedAuthentication", "hostbasedkeytypes": "HostbasedKeyTypes",
+	"hostkeyalgorithms": "HostKeyAlgorithms", "hostkeyalias": "HostKeyAlias", "hostname": "Hostname",
+	"identitiesonly": "IdentitiesOnly", "identityagent": "IdentityAgent",
+	"identityfile": "IdentityFile", "ignoreunknown": "IgnoreUnknown", "include": "Include",
+	"ipqos": "IPQoS", "kbdinteractiveauthentication": "KbdInteractiveAuthentication",
+	"kbdinteractivedevices": "KbdInteractiveDevices", "kexalgorithms": "KexAlgorithms",
+	"knownhostscommand": "KnownHostsCommand", "localcommand": "LocalCommand",
+	"localforward": "LocalForward", "loglevel": "LogLevel", "logverbose": "LogVerbose",
+	"macs": "MACs", "match": "Match",
+	"nohostauthenticationforlocalhost": "NoHostAuthenticationForLocalhost",
+	"numberofpasswordprompts": "NumberOfPasswordPrompts",
+	"passwordauthentication": "PasswordAuthentication", "permitlocalcommand": "PermitLocalCommand",
+	"permitremoteopen": "PermitRemoteOpen", "pkcs11provider": "PKCS11Provider", "port": "Port",
+	"preferredauthentications": "PreferredAuthentications", "proxycommand": "ProxyCommand",
+	"proxyjump": "ProxyJump", "proxyusefdpass": "ProxyUseFdpass",
+	"pubkeyacceptedalgorithms": "PubkeyAcceptedAlgorithms",
+	"pubkeyacceptedkeytypes": "PubkeyAcceptedKeyTypes",
+	"pubkeyauthentication": "PubkeyAuthentication", "rekeylimit": "RekeyLimit",
+	"remotecommand": "RemoteCommand", "remoteforward": "RemoteForward", "requesttty": "RequestTTY",
+	"requiredrsasize": "RequiredRSASize", "revokedhostkeys": "RevokedHostKeys",
+	"securitykeyprovider": "SecurityKeyProvider", "sendenv": "SendEnv",
+	"serveralivecountmax": "ServerAliveCountMax", "serveraliveinterval": "ServerAliveInterval",
+	"sessiontype": "SessionType", "setenv": "SetEnv", "smartcarddevice": "SmartcardDevice",
+	"stdinnull": "StdinNull", "streamlocalbindmask": "StreamLocalBindMask",
+	"streamlocalbindunlink": "StreamLocalBindUnlink",
+	"stricthostkeychecking": "StrictHostKeyChecking", "syslogfacility": "SyslogFacility",
+	"tcpkeepalive": "TCPKeepAlive", "tag": "Tag", "tunnel": "Tunnel", "tunneldevice": "TunnelDevice",
+	"updatehostkeys": "UpdateHostKeys", "useprivilegedport": "UsePrivilegedPort", "user": "User",
+	"userknownhostsfile": "UserKnownHostsFile", "verifyhostkeydns": "VerifyHostKeyDNS",
+	"visualhostkey": "VisualHostKey", "xauthlocation": "XAuthLocation",
+}
+
+// Checks if the passed in key is a keyword that the OpenSSH client understands
+func IsKnownKeyword(key string) bool {
+	_, isKnown := knownKeywords[strings.ToLower(key)]
+	return isKnown
+}
+
+// Gets the casing of a keyword that is used in the ssh_config man page
+// Returns the passed in key as it is if it is not a known keyword
+func GetCanonicalKeyword(key string) string {
+	if canonicalKey, isKnown := knownKeywords[strings.ToLower(key)]; isKnown {
+		return canonicalKey
+	}
+	return key
+}
+
+// Finds the known keyword that is the closest to the passed in key, for when a keyword was misspelled
+// Returns an empty string if no keyword is close enough
+func SuggestKeyword(key string) string {
+	lowerKey := strings.ToLower(key)
+	suggestion := ""
+	bestDistance := 3	// Anything further away than 2 edits is most likely not a typo
+
+	for lowerKeyword, canonicalKey := range knownKeywords {
+		currDistance := editDistance(lowerKey, lowerKeyword)
+		if currDistance < bestDistance || (currDistance == bestDistance && suggestion != "" && canonicalKey < suggestion) {
+			bestDistance = currDistance
+			suggestion = canonicalKey
+		}
+	}
+	return suggestion
+}
+
+// Helper method that counts the number of single character edits needed to turn one string into another
+func editDistance(first string, second string) int {
+	prevRow := make([]int, len(second) + 1)
+	for currIndex := range prevRow {
+		prevRow[currIndex] = currIndex
+	}
+
+	for i := 1; i <= len(first); i = i + 1 {
+		currRow := make([]int, len(second) + 1)
+		currRow[0] = i
+		for j := 1; j <= len(second); j = j + 1 {
+			substituteCost := 1
+			if first[i-1] == second[j-1] {
+				substituteCost = 0
+			}
+			currRow[j] = minOf(prevRow[j] + 1, currRow[j-1] + 1, prevRow[j-1] + substituteCost)
+		}
+		prevRow = currRow
+	}
+	return prevRow[len(second)]
+}
+
+// Helper method that gets the smallest of the passed in numbers
+func minOf(first int, others ...int) int {
+	smallest := first
+	for _, currNum := range others {
+		if currNum < smallest {
+			smallest = currNum
+		}
+	}
+	return smallest
+}
@@ -0,0 +1,106 @@
+package sshmkr_reader
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+	"sshmkr/templates"
+)
+
+// Constants
+const LOCK_SUFFIX = ".sshmkr-lock"
+const LOCK_TIMEOUT = 5 * time.Second
+const LOCK_RETRY_DELAY = 100 * time.Millisecond
+
+// Data struct that holds a lock file that this program has locked, along with how many times it was locked
+type heldLock struct {
+	count int
+	release func()
+}
+
+// The lock files that this program holds, by their location
+// The lock is shared by the whole program, so locking a file that is already held only counts it instead of waiting on itself
+var heldLocks = map[string]*heldLock{}
+var heldLocksMutex sync.Mutex
+
+// Takes an advisory lock on a config file, which is held until the returned unlock function is called (or the program exits)
+// Other sshmkr commands wait for the lock before they read the config, so two commands never change it at the same time
+// The same file can be locked more than once by the program, and the lock is released once every unlock function was called
+// If the lock cannot be taken within a few seconds, an error is returned
+func LockConfigFile(configLoc string) (func(), error) {
+	// Every path to the same file has to share the same lock, so symlinks are followed first
+	targetLoc, err := filepath.EvalSymlinks(configLoc)
+	if err != nil {
+		targetLoc = configLoc
+	}
+	lockLoc := GetLockLoc(targetLoc)
+
+	heldLocksMutex.Lock()
+	defer heldLocksMutex.Unlock()
+	if held, isHeld := heldLocks[lockLoc]; isHeld {
+		held.count = held.count + 1
+		return getUnlockFunc(lockLoc), nil
+	}
+
+	waitUntil := time.Now().Add(LOCK_TIMEOUT)
+	for {
+		release, hasLock, err := tryLockFile(lockLoc)
+		if err != nil {
+			return nil, sshmkr_templates.NewConfigError(sshmkr_templates.ErrFileAccess, "The lock file", lockLoc, "cannot be opened!", err)
+		} else if hasLock {
+			heldLocks[lockLoc] = &heldLock{count: 1, release: release}
+			return getUnlockFunc(lockLoc), nil
+		} else if time.Now().After(waitUntil) {
+			return nil, sshmkr_templates.NewConfigError(sshmkr_templates.ErrConcurrentChange, "Another sshmkr is changing", configLoc, "right now! Please try again once it is done.")
+		}
+		time.Sleep(LOCK_RETRY_DELAY)
+	}
+}
+
+// Helper method that makes the unlock function of a held lock file, which only counts once no matter how often it is called
+func getUnlockFunc(lockLoc string) func() {
+	hasUnlocked := false
+	return func() {
+		heldLocksMutex.Lock()
+		defer heldLocksMutex.Unlock()
+		if hasUnlocked {
+			return
+		}
+		hasUnlocked = true
+
+		held := heldLocks[lockLoc]
+		held.count = held.count - 1
+		if held.count <= 0 {
+			held.release()
+			delete(heldLocks, lockLoc)
+		}
+	}
+}
+
+// Gets the location of the lock file of a config file
+func GetLockLoc(configLoc string) string {
+	return GetSideFileLoc(configLoc, LOCK_SUFFIX)
+}
+
+// Checks that none of the passed in files were changed on disk since they were read, and returns an error if any of them were
+// This catches editors (or sshmkr without locking) that write the config while a command is running,
+// whose changes would otherwise be overwritten
+func CheckForOutsideChanges(docs []*sshmkr_templates.ConfigDocument) error {
+	for _, currDoc := range docs {
+		currInfo, statErr := os.Stat(currDoc.Path)
+		currContents, err := ioutil.ReadFile(currDoc.Path)
+		if os.IsNotExist(statErr) && currDoc.OrigContents == "" {
+			continue
+		} else if err != nil {
+			return sshmkr_templates.NewConfigError(sshmkr_templates.ErrFileAccess, "The config location:", currDoc.Path, "cannot be read!")
+		}
+
+		if HashContents(string(currContents)) != HashContents(currDoc.OrigContents) {
+			return sshmkr_templates.NewConfigError(sshmkr_templates.ErrConcurrentChange, currDoc.Path, "was changed outside of sshmkr at", currInfo.ModTime().Format("15:04:05"),
+				"while this command was running!\nNothing was written, please run the command again.")
+		}
+	}
+	return nil
+}
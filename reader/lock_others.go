@@ -0,0 +1,30 @@
+//go:build !windows
+// +build !windows
+
+package sshmkr_reader
+
+import (
+	"os"
+	"syscall"
+)
+
+// Helper method that tries to take an exclusive flock on the lock file, without waiting for it
+// The lock file is kept open while the lock is held, and closing it with the returned function releases the lock
+// Returns if the lock was taken
+func tryLockFile(lockLoc string) (func(), bool, error) {
+	lockFile, err := os.OpenFile(lockLoc, os.O_CREATE|os.O_RDWR, DEFAULT_FILE_MODE)
+	if err != nil {
+		return nil, false, err
+	}
+
+	err = syscall.Flock(int(lockFile.Fd()), syscall.LOCK_EX|syscall.LOCK_NB)
+	if err == syscall.EWOULDBLOCK {
+		lockFile.Close()
+		return nil, false, nil
+	} else if err != nil {
+		lockFile.Close()
+		return nil, false, err
+	}
+
+	return func() { lockFile.Close() }, true, nil
+}
@@ -0,0 +1,45 @@
+package sshmkr_reader
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLockConfigFileReleases(t *testing.T) {
+	tempDir, err := ioutil.TempDir("", "sshmkr-lock")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tempDir)
+	configLoc := filepath.Join(tempDir, "config")
+	writeTestFile(t, configLoc, "Host web\n")
+	lockLoc := GetLockLoc(configLoc)
+
+	unlock, err := LockConfigFile(configLoc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	unlockAgain, err := LockConfigFile(configLoc)
+	if err != nil {
+		t.Fatalf("locking a held file again error = %v", err)
+	}
+
+	unlock()
+	unlock()
+	if _, isHeld := heldLocks[lockLoc]; !isHeld {
+		t.Fatal("the lock was released while it was still locked a second time")
+	}
+	unlockAgain()
+	if _, isHeld := heldLocks[lockLoc]; isHeld {
+		t.Fatal("the lock is still held after every unlock function was called")
+	}
+
+	// Another open of the lock file can take it once it was released
+	release, hasLock, err := tryLockFile(lockLoc)
+	if err != nil || !hasLock {
+		t.Fatalf("tryLockFile() after unlocking = %v, %v", hasLock, err)
+	}
+	release()
+}
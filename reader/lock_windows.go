@@ -0,0 +1,27 @@
+package sshmkr_reader
+
+import (
+	"syscall"
+)
+
+// Windows error code for a file that another process has open without sharing it
+const ERROR_SHARING_VIOLATION = syscall.Errno(32)
+
+// Helper method that tries to open the lock file without sharing it with any other process, without waiting for it
+// Windows does not have flock, so the file being open is what holds the lock, and closing it with the returned function releases it
+// Returns if the lock was taken
+func tryLockFile(lockLoc string) (func(), bool, error) {
+	lockPath, err := syscall.UTF16PtrFromString(lockLoc)
+	if err != nil {
+		return nil, false, err
+	}
+
+	lockHandle, err := syscall.CreateFile(lockPath, syscall.GENERIC_READ|syscall.GENERIC_WRITE, 0, nil, syscall.OPEN_ALWAYS, syscall.FILE_ATTRIBUTE_NORMAL, 0)
+	if err == ERROR_SHARING_VIOLATION {
+		return nil, false, nil
+	} else if err != nil {
+		return nil, false, err
+	}
+
+	return func() { syscall.CloseHandle(lockHandle) }, true, nil
+}
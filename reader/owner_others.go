@@ -0,0 +1,25 @@
+//go:build !windows
+// +build !windows
+
+package sshmkr_reader
+
+import (
+	"os"
+	"syscall"
+)
+
+// Gives the passed in file the same owner as the original file
+// Changing the owner is only possible as root, so a failed change is not treated as an error
+// if the file is already owned by us
+func copyOwner(file *os.File, origInfo os.FileInfo) error {
+	origStat, ok := origInfo.Sys().(*syscall.Stat_t)
+	if !ok {
+		return nil
+	}
+
+	err := file.Chown(int(origStat.Uid), int(origStat.Gid))
+	if err != nil && int(origStat.Uid) == os.Getuid() {
+		return nil
+	}
+	return err
+}
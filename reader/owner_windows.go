@@ -0,0 +1,10 @@
+package sshmkr_reader
+
+import (
+	"os"
+)
+
+// Windows does not have unix style owners, so there is nothing to copy over
+func copyOwner(file *os.File, origInfo os.FileInfo) error {
+	return nil
+}
@@ -1,8 +1,6 @@
 package sshmkr_reader
 
 import (
-	"fmt"
-	"os"
 	"strings"
 	"io/ioutil"
 	"github.com/kevinburke/ssh_config"
@@ -14,162 +12,370 @@ const MAIN_HEADER_IND = "####"
 const SUB_HEADER_IND = "##"
 const COMMENT_IND = "#"
 
-// Writes out the passed in string into a new file
-func WriteToConfigFile(configLoc string, fileContents string) {
-	err := ioutil.WriteFile(configLoc, []byte(fileContents), 644)
+// Reads and parses the passed config file location to the program
+// Returns the parsed document of the config file
+func ReadConfigDocument(configLoc string) (*sshmkr_templates.ConfigDocument, error) {
+	fileContents, err := ioutil.ReadFile(configLoc)
 	if err != nil {
-		fmt.Println("Error! The location", configLoc, " cannot be written!")
-		os.Exit(1)
+		return nil, sshmkr_templates.NewConfigError(sshmkr_templates.ErrFileAccess, "The config location:", configLoc, "cannot be read!")
 	}
+
+	doc := ParseConfigDocument(fileContents)
+	doc.Path = configLoc
+	doc.OrigContents = string(fileContents)
+	return doc, nil
+}
+
+// Takes in the contents of a ssh config and turns it into a document of headers, comments and host blocks
+// Serializing the returned document without any changes gives back the exact same contents
+func ParseConfigDocument(fileContents []byte) *sshmkr_templates.ConfigDocument {
+	doc := &sshmkr_templates.ConfigDocument{}
+	var currBlock *sshmkr_templates.ConfigEntry
+	pendingLines := []*sshmkr_templates.ConfigLine{}	// Lines after a block that may still be a part of it
+	pendingComments := []*sshmkr_templates.ConfigLine{}	// Comments that may be attached to the next block
+
+	// Places the comments that did not end up attached to a block into the document
+	flushComments := func() {
+		for _, currComment := range pendingComments {
+			doc.Entries = append(doc.Entries, &sshmkr_templates.ConfigEntry{Kind: currComment.Kind, Lines: []*sshmkr_templates.ConfigLine{currComment}})
+		}
+		pendingComments = []*sshmkr_templates.ConfigLine{}
+	}
+
+	// Places a line that is not a part of the current block into the document
+	addLine := func(line *sshmkr_templates.ConfigLine) {
+		if line.Kind == sshmkr_templates.HostLine || line.Kind == sshmkr_templates.MatchLine {
+			currBlock = &sshmkr_templates.ConfigEntry{Kind: line.Kind, Comments: pendingComments, Lines: []*sshmkr_templates.ConfigLine{line}}
+			doc.Entries = append(doc.Entries, currBlock)
+			pendingComments = []*sshmkr_templates.ConfigLine{}
+		} else if line.Kind == sshmkr_templates.CommentLine || (line.Kind == sshmkr_templates.OptionLine && line.Commented) {
+			pendingComments = append(pendingComments, line)
+		} else {
+			flushComments()
+			doc.Entries = append(doc.Entries, &sshmkr_templates.ConfigEntry{Kind: line.Kind, Lines: []*sshmkr_templates.ConfigLine{line}})
+		}
+	}
+
+	for _, currRawLine := range strings.Split(string(fileContents), "\n") {
+		currLine := ParseConfigLine(currRawLine)
+
+		if currBlock != nil {
+			if currLine.Kind == sshmkr_templates.OptionLine && currLine.Commented == currBlock.IsCommented() {
+				// Anything that we held onto is in the middle of the block, so it becomes a part of it
+				currBlock.Lines = append(currBlock.Lines, pendingLines...)
+				currBlock.Lines = append(currBlock.Lines, currLine)
+				pendingLines = []*sshmkr_templates.ConfigLine{}
+				continue
+			} else if !currBlock.IsCommented() && (currLine.Kind == sshmkr_templates.BlankLine || currLine.Kind == sshmkr_templates.CommentLine || currLine.Kind == sshmkr_templates.OptionLine) {
+				// We do not know yet if these lines are in the middle or after the block
+				// Commented out blocks always end at the first line that is not commented out
+				pendingLines = append(pendingLines, currLine)
+				continue
+			}
+
+			// We reached the end of the block, so everything we held onto is placed after it
+			currBlock = nil
+			for _, currPendingLine := range pendingLines {
+				addLine(currPendingLine)
+			}
+			pendingLines = []*sshmkr_templates.ConfigLine{}
+		}
+		addLine(currLine)
+	}
+
+	currBlock = nil
+	for _, currPendingLine := range pendingLines {
+		addLine(currPendingLine)
+	}
+	flushComments()
+
+	return doc
+}
+
+// Parses a single line of a ssh config into its kind, key, value and inline comment
+func ParseConfigLine(rawLine string) *sshmkr_templates.ConfigLine {
+	line := &sshmkr_templates.ConfigLine{Raw: rawLine}
+	trimmedLine := strings.TrimSpace(rawLine)
+
+	// Headers need a space between the indicator and their name, so a bare #### or ## is just a comment
+	if trimmedLine == "" {
+		line.Kind = sshmkr_templates.BlankLine
+		return line
+	} else if strings.HasPrefix(trimmedLine, MAIN_HEADER_IND + " ") {
+		line.Kind = sshmkr_templates.MainHeaderLine
+		return line
+	} else if strings.HasPrefix(trimmedLine, SUB_HEADER_IND + " ") {
+		line.Kind = sshmkr_templates.SubHeaderLine
+		return line
+	}
+
+	content := trimmedLine
+	if strings.HasPrefix(content, COMMENT_IND) {
+		line.Commented = true
+		content = content[len(COMMENT_IND):]
+		line.Indent = content[:len(content) - len(strings.TrimLeft(content, " \t"))]
+	} else {
+		line.Indent = rawLine[:len(rawLine) - len(strings.TrimLeft(rawLine, " \t"))]
+	}
+	line.Key, line.Separator, line.Value, line.Comment = splitConfigLine(strings.TrimLeft(content, " \t"))
+
+	// Commented out Host/Match lines are always placed right after the #, which is how the comment command writes them
+	isBlockStart := !line.Commented || line.Indent == ""
+	if strings.EqualFold(line.Key, "Host") && isBlockStart {
+		line.Kind = sshmkr_templates.HostLine
+	} else if strings.EqualFold(line.Key, "Match") && isBlockStart {
+		line.Kind = sshmkr_templates.MatchLine
+	} else if !line.Commented || IsKnownKeyword(line.Key) {
+		line.Kind = sshmkr_templates.OptionLine
+	} else {
+		line.Kind = sshmkr_templates.CommentLine
+	}
+	return line
 }
 
-// Parses the passed config file location to the program
-// Returns the open file, the file contents, and the decoded config file
-func ParseConfigFile(configLoc string) (*os.File, []byte ,*ssh_config.Config) {
-	config_file, err1 := os.Open(configLoc)
-	config_file_contents, err2 := ioutil.ReadFile(configLoc)
-	config_file_decoded, err3 := ssh_config.Decode(config_file)
-
-	if err1 != nil {
-		fmt.Println("Error! The config location: ", configLoc, " cannot be opned!")
-		os.Exit(1)
-	} else if err2 != nil {
-		fmt.Println("Error! The config location: ", configLoc, " cannot be read!")
-		os.Exit(1)
-	}else if err3 != nil {
-		fmt.Println("Error! The config location: ", configLoc, " cannot be parsed!")
-		os.Exit(1)
-	}
-
-	return config_file, config_file_contents, config_file_decoded
+// Helper method that splits the content of a line into its key, separator, value and inline comment
+func splitConfigLine(content string) (string, string, string, string) {
+	keyEnd := strings.IndexAny(content, " \t=")
+	if keyEnd == -1 {
+		return content, "", "", ""
+	}
+
+	// The separator is any amount of whitespace with at most one = in it
+	valueStart := keyEnd
+	foundEquals := false
+	for valueStart < len(content) {
+		currChar := content[valueStart]
+		if currChar == '=' && !foundEquals {
+			foundEquals = true
+		} else if currChar != ' ' && currChar != '\t' {
+			break
+		}
+		valueStart = valueStart + 1
+	}
+
+	// Inline comments start at a # that comes after whitespace and is not in quotes
+	rest := content[valueStart:]
+	commentStart := len(rest)
+	inQuotes := false
+	for currIndex := 0; currIndex < len(rest); currIndex = currIndex + 1 {
+		if rest[currIndex] == '"' {
+			inQuotes = !inQuotes
+		} else if rest[currIndex] == '#' && !inQuotes && currIndex > 0 && (rest[currIndex-1] == ' ' || rest[currIndex-1] == '\t') {
+			commentStart = currIndex
+			break
+		}
+	}
+
+	value := strings.TrimRight(rest[:commentStart], " \t")
+	comment := ""
+	if commentStart < len(rest) {
+		comment = rest[len(value):]
+	}
+	return content[:keyEnd], content[keyEnd:valueStart], value, comment
+}
+
+// Splits the value of a config line into its space separated arguments, the same way ssh does
+// An argument in double quotes keeps the spaces in it, i.e the command of a Match exec, and the quotes are left out
+func SplitConfigValue(value string) []string {
+	arguments := []string{}
+	currArgument := ""
+	hasArgument := false
+	inQuotes := false
+	for currIndex := 0; currIndex < len(value); currIndex = currIndex + 1 {
+		currChar := value[currIndex]
+		if currChar == '"' {
+			inQuotes = !inQuotes
+			hasArgument = true
+		} else if (currChar == ' ' || currChar == '\t') && !inQuotes {
+			if hasArgument {
+				arguments = append(arguments, currArgument)
+			}
+			currArgument = ""
+			hasArgument = false
+		} else {
+			currArgument = currArgument + string(currChar)
+			hasArgument = true
+		}
+	}
+	if hasArgument {
+		arguments = append(arguments, currArgument)
+	}
+	return arguments
 }
 
 // Takes in a parsed ssh config and outputs all of the relevant header comments
 // Returns an array of headerBlocks, which are logical groupings of ssh configs
-func ParseConfigHeaders(fileContents []byte) []sshmkr_templates.HeaderBlock {
-	fileContentsArray := strings.Split(string(fileContents), "\n")
-	
-	headerBlocks := make([]sshmkr_templates.HeaderBlock, len(fileContentsArray))
-	headerBlocksSize := 0
-
-	newHeaderBlock := ""
-	newSubHeadersBlock := ""	// sub headers will be stored in one string, formatted by \n
-	lastHeader := ""			// Saves a ref to the last parsed header value
-	
-	for currIndex, currLine := range fileContentsArray {
-		
-		if currIndex + 1 == len(fileContentsArray) {
-			// Once we reached the end of the file, we parse out the last remaining HeaderBlock
-			newSubHeadersBlock = strings.TrimRight(newSubHeadersBlock, "\n")
-			splitHeaders := strings.Split(newSubHeadersBlock, "\n")
-			headerBlocks[headerBlocksSize] = sshmkr_templates.HeaderBlock{MainHeader: newHeaderBlock, SubHeaders: splitHeaders}
-			headerBlocksSize = headerBlocksSize + 1
-		} else {
-			commentStartIndex := strings.Index(currLine, " ")
-			if commentStartIndex != -1 {
-				// We come in here once we are on a line that is formatted to be a header
-				parsedLine := currLine[:commentStartIndex]
-
-				if parsedLine == MAIN_HEADER_IND {
-					if lastHeader == SUB_HEADER_IND {
-						// If we reach a new header block, we save the HeaderBlock that we made
-						// to the array and start making a new one
-						newSubHeadersBlock = strings.TrimRight(newSubHeadersBlock, "\n")
-						splitHeaders := strings.Split(newSubHeadersBlock, "\n")
-						headerBlocks[headerBlocksSize] = sshmkr_templates.HeaderBlock{MainHeader: newHeaderBlock, SubHeaders: splitHeaders}
-						headerBlocksSize = headerBlocksSize + 1
-					}
-					
-					// We encounted a main header, making it the start of a new HeaderBlock obj
-					newHeaderBlock = currLine
-					newSubHeadersBlock = ""
-					lastHeader = parsedLine
-					
-				} else if parsedLine == SUB_HEADER_IND && newHeaderBlock != "" {
-					// We are adding more to the current HeaderBlock that we are making
-					// As long as we are in a new HeaderBlock
-					newSubHeadersBlock = newSubHeadersBlock + currLine + "\n"
-					lastHeader = parsedLine
-				}
-			} 
-		} 
+func ParseConfigHeaders(files *sshmkr_templates.ConfigFiles) []sshmkr_templates.HeaderBlock {
+	headerBlocks := []sshmkr_templates.HeaderBlock{}
+
+	// Only the headers of the tree are used, and anything outside of a main header is skipped
+	for _, currBlock := range ParseConfigTree(files) {
+		if currBlock.MainHeader == "" {
+			continue
+		}
+		if len(currBlock.SubHeaders) > 0 && currBlock.SubHeaders[0] == "" {
+			currBlock.SubHeaders = currBlock.SubHeaders[1:]
+			currBlock.Hosts = currBlock.Hosts[1:]
+		}
+		headerBlocks = append(headerBlocks, currBlock)
 	}
 
-	// We return a slice of the formed headerblocks that we formed
-	return headerBlocks[:headerBlocksSize]
+	return headerBlocks
 }
 
 // Returns a ConfigTemplate object that contains information on a given template
-func ReadSpecificTemplate(hostname string, config_template *ssh_config.Config) sshmkr_templates.ConfigTemplate {
-	for _, host := range config_template.Hosts {
-		if  CheckIfExistingHostname(hostname, host.Patterns[0].String()) {
-			currIndex := 0
-			formatted_template_string := "\nHost %s\n"
-
-			// Because we are manually adding the host value in, we need to account for that
-			// in the total length of the template
-			lenOfTemplate := len(host.Nodes) + 1
-			template_kv := make([]ssh_config.KV, lenOfTemplate)
-			
-			template_kv[currIndex] = ssh_config.KV{Key: "Host", Value: hostname, Comment: ""}
-			currIndex = currIndex + 1
-			for _, node := range host.Nodes {
-				nodeRendered := strings.TrimLeft(node.String(), " ")
-				
-				if CheckIfValid(nodeRendered) && currIndex < lenOfTemplate {
-					// In order to parse the node's key and value, we use indexing
-					// Knowing that we are using the first space as the division, we use that as the point of index
-					nodeDivider := strings.Index(nodeRendered, " ")
-					nodeKey := nodeRendered[0:nodeDivider]
-					nodeValue := nodeRendered[nodeDivider+1:]
-					
-					formatted_template_string = formatted_template_string + "\t" + nodeKey + " %s \n"
-					
-					// The order of the interpolation in the format template string also correlates
-					// to the order of the values in the array
-					template_kv[currIndex] = ssh_config.KV{Key: nodeKey, Value: nodeValue, Comment: ""}
-					currIndex = currIndex + 1
-				}
-			}
-			// We then create a struct object from the data we gathered and return it out
-			// Note that the length of the default values is always the same number as the
-			// numbrrt of special replacement chars
-			return sshmkr_templates.ConfigTemplate{KeyPairs: template_kv[:currIndex], FormattedString: formatted_template_string}
+// The name has to be given, since picking a template for the user is left to the template selection of the add command
+// The comments of the host are not read as annotations, since this is also used for the hosts of the ssh_config (see ReadAnnotatedTemplate)
+func ReadSpecificTemplate(hostname string, config_template *sshmkr_templates.ConfigFiles) (sshmkr_templates.ConfigTemplate, error) {
+	if len(hostname) <= 0 {
+		return sshmkr_templates.ConfigTemplate{}, sshmkr_templates.NewConfigError(sshmkr_templates.ErrInvalidInput, "Source flag is empty! Please pass in the name of the template or host to use!")
+	}
+
+	entry := findTemplateEntry(hostname, config_template)
+	if entry != nil {
+		blockKey := "Host"
+		if entry.Kind == sshmkr_templates.MatchLine {
+			blockKey = "Match"
 		}
+
+		// All of the aliases of the host are kept, so they can be edited together
+		template_kv := []ssh_config.KV{ssh_config.KV{Key: blockKey, Value: entry.GetHeaderLine().Value, Comment: ""}}
+		for _, option := range entry.GetOptions() {
+			template_kv = append(template_kv, ssh_config.KV{Key: option.Key, Value: option.Value, Comment: ""})
+		}
+
+		// We then create a struct object from the data we gathered and return it out
+		return sshmkr_templates.ConfigTemplate{KeyPairs: template_kv}, nil
 	}
 
 	// Only comes here if the passed in template name does not match any existing ones
-	fmt.Println("Cannot find template", hostname, "in config_templates file! Typo maybe?")
-	os.Exit(-1)
-
-	// Even though it will never reach here, we have to put a return value here
-	return sshmkr_templates.ConfigTemplate{}
+	return sshmkr_templates.ConfigTemplate{}, sshmkr_templates.NewConfigError(sshmkr_templates.ErrTemplateNotFound, "Cannot find template", hostname, "in config_templates file! Typo maybe?")
 }
 
-// Helper method that checks if the given line is not a comment or empty
-func CheckIfValid(line string) bool {
-	if line != "" && !strings.Contains(line, COMMENT_IND) && !strings.Contains(line, MAIN_HEADER_IND) && !strings.Contains(line, SUB_HEADER_IND) {
+// Helper method that finds the block of a template or host by its name, or any of its aliases
+// Returns nil if there is no such block
+func findTemplateEntry(hostname string, config_template *sshmkr_templates.ConfigFiles) *sshmkr_templates.ConfigEntry {
+	// We go through the templates in the same order that ssh would read them
+	var entry *sshmkr_templates.ConfigEntry
+	config_template.WalkEntries(func(doc *sshmkr_templates.ConfigDocument, index int) bool {
+		currEntry := doc.Entries[index]
+		if !currEntry.IsBlock() || currEntry.IsCommented() {
+			return true
+		} else if currEntry.Kind == sshmkr_templates.MatchLine {
+			if CheckIfExistingHostname(hostname, currEntry.GetName()) {
+				entry = currEntry
+				return false
+			}
+			return true
+		}
+
+		// A template can be looked up by any of its aliases
+		for _, currPattern := range currEntry.GetPatterns() {
+			if CheckIfExistingHostname(hostname, currPattern) {
+				entry = currEntry
+				return false
+			}
+		}
 		return true
-	}
-	return false
+	})
+	return entry
 }
 
 // Helper function that will be used to verify the passed in hostname
-// Returns true if we have a match or we pass in nothing (which defaults to the first template)
+// Returns true if we have a match
 func CheckIfExistingHostname(checkHostname string, verifiedHostname string) bool {
 	if verifiedHostname == "*" {
 		// This seems to be a little thing regarding the SSH reader I'm using, which is why this test is here
 		// Specifically, this is the default value for all hosts?
 		return false
 	} else if verifiedHostname == checkHostname {
-		fmt.Println("Found host config to use for template,", checkHostname, "...")
-		fmt.Println("")
-		return true
-	} else if len(checkHostname) <= 0 {
-		fmt.Println("No hostname passed in, defaulting to first template...")
-		fmt.Println("")
 		return true
 	} else {
 		return false
 	}
-}
\ No newline at end of file
+}
+
+// Takes in a parsed ssh config and outputs every header along with the hosts under them
+// Each file is gone through on its own, and hosts that are placed before any main/sub header
+// in a file are grouped under an empty header
+// Returns an array of headerBlocks, with the Hosts field filled out
+func ParseConfigTree(files *sshmkr_templates.ConfigFiles) []sshmkr_templates.HeaderBlock {
+	headerBlocks := []sshmkr_templates.HeaderBlock{}
+	for _, currDoc := range files.Docs {
+		headerBlocks = append(headerBlocks, parseDocTree(currDoc)...)
+	}
+	return headerBlocks
+}
+
+// Helper method that outputs every header of a single file along with the hosts under them
+func parseDocTree(doc *sshmkr_templates.ConfigDocument) []sshmkr_templates.HeaderBlock {
+	headerBlocks := []sshmkr_templates.HeaderBlock{}
+
+	for _, currEntry := range doc.Entries {
+		switch currEntry.Kind {
+			case sshmkr_templates.MainHeaderLine:
+				headerBlocks = append(headerBlocks, sshmkr_templates.HeaderBlock{MainHeader: strings.TrimSpace(currEntry.Lines[0].Raw)})
+			case sshmkr_templates.SubHeaderLine:
+				headerBlocks = ensureTreeSubHeader(headerBlocks, strings.TrimSpace(currEntry.Lines[0].Raw), true)
+			case sshmkr_templates.HostLine, sshmkr_templates.MatchLine:
+				// A host is placed under the last seen sub header
+				headerBlocks = ensureTreeSubHeader(headerBlocks, "", false)
+				lastBlock := &headerBlocks[len(headerBlocks)-1]
+				lastSub := len(lastBlock.Hosts) - 1
+
+				summaryName := currEntry.GetHeaderLine().Value
+				if currEntry.Kind == sshmkr_templates.MatchLine {
+					summaryName = currEntry.GetName()
+				}
+
+				newSummary := sshmkr_templates.HostSummary{
+					Name: summaryName,
+					Hostname: currEntry.GetOption("Hostname"),
+					User: currEntry.GetOption("User"),
+					Port: currEntry.GetOption("Port"),
+					Commented: currEntry.IsCommented(),
+				}
+				lastBlock.Hosts[lastSub] = append(lastBlock.Hosts[lastSub], newSummary)
+		}
+	}
+
+	for currIndex := range headerBlocks {
+		headerBlocks[currIndex].File = doc.Path
+	}
+	return headerBlocks
+}
+
+// Helper method that makes sure the last HeaderBlock in the tree has a sub header to place hosts in
+// If forceNew is true, a new sub header is always appended with the passed in name
+func ensureTreeSubHeader(headerBlocks []sshmkr_templates.HeaderBlock, subHeader string, forceNew bool) []sshmkr_templates.HeaderBlock {
+	if len(headerBlocks) == 0 {
+		headerBlocks = append(headerBlocks, sshmkr_templates.HeaderBlock{})
+	}
+
+	lastBlock := &headerBlocks[len(headerBlocks)-1]
+	if forceNew || len(lastBlock.SubHeaders) == 0 {
+		lastBlock.SubHeaders = append(lastBlock.SubHeaders, subHeader)
+		lastBlock.Hosts = append(lastBlock.Hosts, []sshmkr_templates.HostSummary{})
+	}
+	return headerBlocks
+}
+
+// Strips the header indicator off of a main/sub header, leaving only its name
+func TrimHeaderIndicator(header string) string {
+	header = strings.TrimSpace(header)
+	if strings.HasPrefix(header, MAIN_HEADER_IND + " ") {
+		return strings.TrimSpace(header[len(MAIN_HEADER_IND):])
+	} else if strings.HasPrefix(header, SUB_HEADER_IND + " ") {
+		return strings.TrimSpace(header[len(SUB_HEADER_IND):])
+	}
+	return header
+}
+
+// Splits a "Main Header/Sub Header" path into its main and sub header names
+func SplitHeaderPath(headerPath string) (string, string) {
+	splitPath := strings.SplitN(headerPath, "/", 2)
+	if len(splitPath) == 1 {
+		return strings.TrimSpace(splitPath[0]), ""
+	}
+	return strings.TrimSpace(splitPath[0]), strings.TrimSpace(splitPath[1])
+}
@@ -0,0 +1,180 @@
+package sshmkr_reader
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"sshmkr/templates"
+)
+
+func TestParseConfigDocumentRoundTrip(t *testing.T) {
+	testCases := []struct {
+		name string
+		contents string
+	}{
+		{"empty", ""},
+		{"single host", "Host web\n\tHostName web.example.com\n"},
+		{"no trailing newline", "Host web\n\tHostName web.example.com"},
+		{"headers", "#### Work\n## Servers\nHost web\n\tHostName web.example.com\n\n## Databases\nHost db\n\tUser admin\n"},
+		{"bare header indicators", "####\n##\nHost web\n\tUser me\n"},
+		{"comments", "# Global comment\n\n# Attached to web\nHost web # inline comment\n\t# Inside the block\n\tUser me # after value\n\t#Port 2222\n"},
+		{"commented out block", "#Host old\n#\tHostName old.example.com\n\nHost new\n\tHostName new.example.com\n"},
+		{"odd spacing", "   Host   web   other\n      HostName=web.example.com\n  User   =   me  \n\n\n\nHost *\n ForwardAgent yes\n"},
+		{"tabs", "Host\tweb\n\t\tHostName\tweb.example.com\n\tUser\t\tme\t\n"},
+		{"crlf", "#### Work\r\n## Servers\r\nHost web\r\n\tHostName web.example.com\r\n\r\nHost db\r\n\tUser admin\r\n"},
+		{"match block", "Match host web exec \"test -f /tmp/x\"\n\tUser me\n"},
+		{"global options", "ServerAliveInterval 60\nInclude conf.d/*\n\nHost web\n\tUser me\n"},
+		{"quoted values", "Host web\n\tIdentityFile \"~/.ssh/my key\" # quoted\n\tProxyCommand ssh -W %h:%p \"jump # host\"\n"},
+	}
+
+	for _, currCase := range testCases {
+		t.Run(currCase.name, func(t *testing.T) {
+			doc := ParseConfigDocument([]byte(currCase.contents))
+			if got := doc.String(); got != currCase.contents {
+				t.Errorf("round trip changed the contents\ngot:  %q\nwant: %q", got, currCase.contents)
+			}
+		})
+	}
+}
+
+func TestParseConfigLineKinds(t *testing.T) {
+	testCases := []struct {
+		rawLine string
+		kind sshmkr_templates.LineKind
+	}{
+		{"", sshmkr_templates.BlankLine},
+		{"   \t", sshmkr_templates.BlankLine},
+		{"#### Work", sshmkr_templates.MainHeaderLine},
+		{"## Servers", sshmkr_templates.SubHeaderLine},
+		{"####", sshmkr_templates.CommentLine},
+		{"##", sshmkr_templates.CommentLine},
+		{"#####", sshmkr_templates.CommentLine},
+		{"# just a comment", sshmkr_templates.CommentLine},
+		{"Host web", sshmkr_templates.HostLine},
+		{"host web", sshmkr_templates.HostLine},
+		{"#Host web", sshmkr_templates.HostLine},
+		{"Match host web", sshmkr_templates.MatchLine},
+		{"\tUser me", sshmkr_templates.OptionLine},
+		{"User=me", sshmkr_templates.OptionLine},
+	}
+
+	for _, currCase := range testCases {
+		if got := ParseConfigLine(currCase.rawLine).Kind; got != currCase.kind {
+			t.Errorf("ParseConfigLine(%q).Kind = %v, want %v", currCase.rawLine, got, currCase.kind)
+		}
+	}
+}
+
+func TestReadConfigFilesWithInclude(t *testing.T) {
+	tempDir, err := ioutil.TempDir("", "sshmkr-reader")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	fileContents := map[string]string{
+		"config": "Include conf.d/*.conf\r\n\r\n#### Work\r\n## Servers\r\nHost web\r\n\tUser me\r\n",
+		"conf.d/a.conf": "Host a\n\t HostName   a.example.com # spaced\n",
+		"conf.d/b.conf": "# Only a comment\n",
+		"conf.d/notes.txt": "Host ignored\n",
+	}
+	for currName, currContents := range fileContents {
+		currLoc := filepath.Join(tempDir, currName)
+		if err := os.MkdirAll(filepath.Dir(currLoc), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(currLoc, []byte(currContents), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	files, err := ReadConfigFiles(filepath.Join(tempDir, "config"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files.Docs) != 3 {
+		t.Fatalf("read %d files, want 3", len(files.Docs))
+	}
+	for _, currDoc := range files.Docs {
+		relativeLoc, _ := filepath.Rel(tempDir, currDoc.Path)
+		if got := currDoc.String(); got != fileContents[filepath.ToSlash(relativeLoc)] {
+			t.Errorf("round trip of %s changed the contents\ngot:  %q\nwant: %q", relativeLoc, got, fileContents[filepath.ToSlash(relativeLoc)])
+		}
+		if currDoc.HasChanged() {
+			t.Errorf("%s is marked as changed right after being read", relativeLoc)
+		}
+	}
+
+	if _, _, host := files.FindBlock("a", false); host == nil {
+		t.Errorf("host from an included file was not found")
+	}
+}
+
+func TestSplitConfigValue(t *testing.T) {
+	testCases := []struct {
+		value string
+		want []string
+	}{
+		{"", []string{}},
+		{"web", []string{"web"}},
+		{"web  db\tcache", []string{"web", "db", "cache"}},
+		{"host web exec \"test -f /tmp/x\"", []string{"host", "web", "exec", "test -f /tmp/x"}},
+		{"exec \"nc -z %h 22\" user me", []string{"exec", "nc -z %h 22", "user", "me"}},
+		{"\"\" web", []string{"", "web"}},
+		{"a\"b c\"d", []string{"ab cd"}},
+		{"\"unterminated quote", []string{"unterminated quote"}},
+	}
+
+	for _, currCase := range testCases {
+		if got := SplitConfigValue(currCase.value); !reflect.DeepEqual(got, currCase.want) {
+			t.Errorf("SplitConfigValue(%q) = %q, want %q", currCase.value, got, currCase.want)
+		}
+	}
+}
+
+func TestReadTemplateAnnotations(t *testing.T) {
+	testCases := []struct {
+		name string
+		template string
+		want []sshmkr_templates.KeyAnnotation
+		wantErr bool
+	}{
+		{
+			"no annotations",
+			"# A normal comment\nHost tpl\n\tUser me # about the user\n",
+			[]sshmkr_templates.KeyAnnotation{{}, {}},
+			false,
+		},
+		{
+			"annotations above and after keys",
+			"# @prompt Name of the host\n# @required\nHost tpl\n\t# @choices a, b\n\tUser a\n\tPort 22 # @pattern [0-9]+\n\tForwardAgent no # @fixed\n",
+			[]sshmkr_templates.KeyAnnotation{{Prompt: "Name of the host", Required: true}, {Choices: []string{"a", "b"}}, {Pattern: "[0-9]+"}, {Fixed: true}},
+			false,
+		},
+		{"invalid pattern", "Host tpl\n\tPort 22 # @pattern [0-9\n", nil, true},
+		{"misspelled annotation", "Host tpl\n\t# @requried\n\tUser me\n", nil, true},
+		{"unknown annotation on the host", "# @default web\nHost tpl\n\tUser me\n", nil, true},
+	}
+
+	for _, currCase := range testCases {
+		t.Run(currCase.name, func(t *testing.T) {
+			doc := ParseConfigDocument([]byte(currCase.template))
+			var entry *sshmkr_templates.ConfigEntry
+			for _, currEntry := range doc.Entries {
+				if currEntry.IsBlock() {
+					entry = currEntry
+				}
+			}
+
+			got, err := ReadTemplateAnnotations(entry)
+			if (err != nil) != currCase.wantErr {
+				t.Fatalf("ReadTemplateAnnotations() error = %v, want error %v", err, currCase.wantErr)
+			}
+			if !currCase.wantErr && !reflect.DeepEqual(got, currCase.want) {
+				t.Errorf("ReadTemplateAnnotations() = %+v, want %+v", got, currCase.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,137 @@
+package sshmkr_reader
+
+import (
+	"os"
+	"regexp"
+	"strings"
+	"sshmkr/templates"
+)
+
+// Constants
+const TEMPLATES_SUFFIX = "_templates"
+const ANNOTATION_IND = "@"
+
+// Gets the location of the templates file that belongs to a config file (~/.ssh/config_templates by default)
+func GetTemplatesLoc(configLoc string) string {
+	return configLoc + TEMPLATES_SUFFIX
+}
+
+// Reads and parses the templates file that belongs to a config file, along with every file it includes
+// If allowMissing is true, a templates file that does not exist yet is read as an empty one, so it is created once it is written
+func ReadTemplateFiles(configLoc string, allowMissing bool) (*sshmkr_templates.ConfigFiles, error) {
+	templatesLoc := GetTemplatesLoc(configLoc)
+	if _, err := os.Stat(templatesLoc); os.IsNotExist(err) && allowMissing {
+		emptyDoc := ParseConfigDocument([]byte{})
+		emptyDoc.Path = templatesLoc
+		return &sshmkr_templates.ConfigFiles{Docs: []*sshmkr_templates.ConfigDocument{emptyDoc}, Includes: map[*sshmkr_templates.ConfigLine][]*sshmkr_templates.ConfigDocument{}}, nil
+	}
+	return ReadConfigFiles(templatesLoc)
+}
+
+// Gets the name of every template that is not commented out, in the same order that ssh would read them
+func GetTemplateNames(files *sshmkr_templates.ConfigFiles) []string {
+	templateNames := []string{}
+	files.WalkEntries(func(doc *sshmkr_templates.ConfigDocument, index int) bool {
+		currEntry := doc.Entries[index]
+		if currEntry.IsBlock() && !currEntry.IsCommented() && currEntry.GetName() != "*" {
+			templateNames = append(templateNames, currEntry.GetName())
+		}
+		return true
+	})
+	return templateNames
+}
+
+// Returns the ConfigTemplate of a template in the templates file, the way a new host is made from it
+// Only these get the "# @name value" comments read as annotations and their variables expanded,
+// since the comments of the hosts in the ssh_config are just notes that can start with an @ too
+func ReadAnnotatedTemplate(templateName string, templateFiles *sshmkr_templates.ConfigFiles) (sshmkr_templates.ConfigTemplate, error) {
+	template, err := ReadSpecificTemplate(templateName, templateFiles)
+	if err != nil {
+		return template, err
+	}
+	template.Annotations, err = ReadTemplateAnnotations(findTemplateEntry(templateName, templateFiles))
+	if err != nil {
+		return sshmkr_templates.ConfigTemplate{}, err
+	}
+	template.ExpandVariables = true
+	return template, nil
+}
+
+// Reads the annotations of every key of a template, in the same order as its key pairs (Host/Match first, then each option)
+// The annotations of a key are the "# @name value" comments right above it, or in the inline comment after its value
+// Comments that are not annotations are left alone, so templates can still have normal comments
+func ReadTemplateAnnotations(entry *sshmkr_templates.ConfigEntry) ([]sshmkr_templates.KeyAnnotation, error) {
+	annotations := []sshmkr_templates.KeyAnnotation{}
+
+	headerAnnotation, err := parseKeyAnnotation(entry.GetHeaderLine(), entry.Comments)
+	if err != nil {
+		return nil, err
+	}
+	annotations = append(annotations, headerAnnotation)
+
+	pendingComments := []*sshmkr_templates.ConfigLine{}
+	for _, currLine := range entry.Lines[1:] {
+		if currLine.Kind == sshmkr_templates.CommentLine {
+			pendingComments = append(pendingComments, currLine)
+			continue
+		} else if currLine.Kind == sshmkr_templates.OptionLine && currLine.Commented == entry.IsCommented() {
+			optionAnnotation, err := parseKeyAnnotation(currLine, pendingComments)
+			if err != nil {
+				return nil, err
+			}
+			annotations = append(annotations, optionAnnotation)
+		}
+		// Annotations only belong to the key right below them, so anything else in between drops them
+		pendingComments = []*sshmkr_templates.ConfigLine{}
+	}
+	return annotations, nil
+}
+
+// Helper method that reads the annotations of a single key out of the comments above it and its inline comment
+func parseKeyAnnotation(keyLine *sshmkr_templates.ConfigLine, commentLines []*sshmkr_templates.ConfigLine) (sshmkr_templates.KeyAnnotation, error) {
+	annotation := sshmkr_templates.KeyAnnotation{}
+
+	comments := []string{}
+	for _, currLine := range commentLines {
+		if currLine.Kind == sshmkr_templates.CommentLine {
+			comments = append(comments, currLine.Raw)
+		}
+	}
+	comments = append(comments, keyLine.Comment)
+
+	for _, currComment := range comments {
+		currComment = strings.TrimSpace(strings.TrimLeft(strings.TrimSpace(currComment), COMMENT_IND))
+		if !strings.HasPrefix(currComment, ANNOTATION_IND) {
+			continue
+		}
+		splitComment := strings.SplitN(currComment[len(ANNOTATION_IND):], " ", 2)
+		annotationValue := ""
+		if len(splitComment) > 1 {
+			annotationValue = strings.TrimSpace(splitComment[1])
+		}
+
+		switch strings.ToLower(splitComment[0]) {
+			case "prompt":
+				annotation.Prompt = annotationValue
+			case "required":
+				annotation.Required = true
+			case "fixed":
+				annotation.Fixed = true
+			case "choices":
+				for _, currChoice := range strings.Split(annotationValue, ",") {
+					if currChoice = strings.TrimSpace(currChoice); currChoice != "" {
+						annotation.Choices = append(annotation.Choices, currChoice)
+					}
+				}
+			case "pattern":
+				if _, err := regexp.Compile(annotationValue); err != nil || annotationValue == "" {
+					return annotation, sshmkr_templates.NewConfigError(sshmkr_templates.ErrInvalidInput, "The @pattern of", keyLine.Key, "is not a valid regular expression!", annotationValue)
+				}
+				annotation.Pattern = annotationValue
+			default:
+				// A misspelled annotation would otherwise be skipped without the template author ever knowing
+				return annotation, sshmkr_templates.NewConfigError(sshmkr_templates.ErrInvalidInput, "Unknown annotation", ANNOTATION_IND + splitComment[0], "on", keyLine.Key, "! Available annotations are: [@prompt, @required, @choices, @pattern, @fixed]")
+		}
+	}
+	return annotation, nil
+}
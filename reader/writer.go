@@ -0,0 +1,161 @@
+package sshmkr_reader
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sshmkr/templates"
+)
+
+// Constants
+const BACKUP_SUFFIX = ".sshmkr-bak"
+const DEFAULT_BACKUP_COUNT = 5
+const DEFAULT_FILE_MODE = 0600
+
+// Writes out the passed in string into the config file
+// The contents are written to a temp file first and then renamed over the config, so the config is never half written
+// The previous contents of the config are kept in a rotating set of backups, up to backupCount of them
+func WriteToConfigFile(configLoc string, fileContents string, backupCount int) error {
+	// If the config is a symlink, we write to the file that it points to so the link stays intact
+	targetLoc, err := filepath.EvalSymlinks(configLoc)
+	if err != nil {
+		targetLoc = configLoc
+	}
+
+	fileMode := os.FileMode(DEFAULT_FILE_MODE)
+	origInfo, statErr := os.Stat(targetLoc)
+	if statErr == nil {
+		fileMode = origInfo.Mode().Perm()
+		if backupCount > 0 {
+			if err := rotateBackups(targetLoc, origInfo, backupCount); err != nil {
+				return err
+			}
+		}
+	}
+
+	tempFile, err := ioutil.TempFile(filepath.Dir(targetLoc), fmt.Sprintf(".%s.sshmkr-tmp-*", filepath.Base(targetLoc)))
+	if err != nil {
+		return sshmkr_templates.NewConfigError(sshmkr_templates.ErrFileAccess, "The location", configLoc, "cannot be written!")
+	}
+	tempLoc := tempFile.Name()
+
+	_, err = tempFile.WriteString(fileContents)
+	if err == nil {
+		err = tempFile.Chmod(fileMode)
+	}
+	if err == nil && statErr == nil {
+		err = copyOwner(tempFile, origInfo)
+	}
+	if err == nil {
+		err = tempFile.Sync()
+	}
+	closeErr := tempFile.Close()
+	if err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tempLoc, targetLoc)
+	}
+
+	if err != nil {
+		os.Remove(tempLoc)
+		return sshmkr_templates.NewConfigError(sshmkr_templates.ErrFileAccess, "The location", configLoc, "cannot be written!")
+	}
+	syncDir(filepath.Dir(targetLoc))
+	return nil
+}
+
+// Gets the location of a file that sshmkr keeps next to a config file, i.e ~/.ssh/.config.sshmkr-journal
+// These files are hidden, so they are never matched by an Include glob (i.e config.d/*) and read by ssh
+func GetSideFileLoc(configLoc string, suffix string) string {
+	return filepath.Join(filepath.Dir(configLoc), fmt.Sprintf(".%s%s", filepath.Base(configLoc), suffix))
+}
+
+// Gets the location of the Nth backup of a config file, with 1 being the newest
+func GetBackupLoc(configLoc string, backupNum int) string {
+	return GetSideFileLoc(configLoc, fmt.Sprintf("%s.%d", BACKUP_SUFFIX, backupNum))
+}
+
+// Helper method that gets where older versions of sshmkr kept a side file, which was right next to the config without being hidden
+func getLegacySideFileLoc(sideFileLoc string) string {
+	return filepath.Join(filepath.Dir(sideFileLoc), strings.TrimPrefix(filepath.Base(sideFileLoc), "."))
+}
+
+// Helper method that reads a side file of a config
+// If it does not exist, the one that an older version of sshmkr left in its place is read instead
+func readSideFile(sideFileLoc string) ([]byte, error) {
+	fileContents, err := ioutil.ReadFile(sideFileLoc)
+	if os.IsNotExist(err) {
+		if legacyContents, legacyErr := ioutil.ReadFile(getLegacySideFileLoc(sideFileLoc)); !os.IsNotExist(legacyErr) {
+			return legacyContents, legacyErr
+		}
+	}
+	return fileContents, err
+}
+
+// Helper method that writes out a side file of a config, which is always kept private
+// The copy that an older version of sshmkr left in its place is removed, since everything in it was read into the new one
+func writeSideFile(sideFileLoc string, fileContents string) error {
+	err := WriteToConfigFile(sideFileLoc, fileContents, 0)
+	if err != nil {
+		return err
+	}
+	os.Chmod(sideFileLoc, DEFAULT_FILE_MODE)
+	os.Remove(getLegacySideFileLoc(sideFileLoc))
+	return nil
+}
+
+// Helper method that shifts every backup of a config up by one and saves the current config as the newest one
+// Backups past the retention count are removed
+func rotateBackups(configLoc string, origInfo os.FileInfo, backupCount int) error {
+	origContents, err := ioutil.ReadFile(configLoc)
+	if err != nil {
+		return sshmkr_templates.NewConfigError(sshmkr_templates.ErrFileAccess, "The location", configLoc, "cannot be backed up!")
+	}
+
+	// Backups made by older versions of sshmkr are moved to where they are kept now, so they are still rotated
+	for backupNum := 1; ; backupNum = backupNum + 1 {
+		legacyLoc := getLegacySideFileLoc(GetBackupLoc(configLoc, backupNum))
+		if _, err := os.Stat(legacyLoc); err != nil {
+			break
+		}
+		if _, err := os.Stat(GetBackupLoc(configLoc, backupNum)); os.IsNotExist(err) {
+			os.Rename(legacyLoc, GetBackupLoc(configLoc, backupNum))
+		}
+	}
+
+	// We also clean up backups that were kept from a larger retention count
+	for backupNum := backupCount; ; backupNum = backupNum + 1 {
+		if _, err := os.Stat(GetBackupLoc(configLoc, backupNum)); err != nil {
+			break
+		}
+		os.Remove(GetBackupLoc(configLoc, backupNum))
+	}
+
+	for backupNum := backupCount - 1; backupNum >= 1; backupNum = backupNum - 1 {
+		os.Rename(GetBackupLoc(configLoc, backupNum), GetBackupLoc(configLoc, backupNum + 1))
+	}
+
+	// The backup keeps the timestamp of the config it was made from, so we know when that version was written
+	newestBackup := GetBackupLoc(configLoc, 1)
+	err = ioutil.WriteFile(newestBackup, origContents, origInfo.Mode().Perm())
+	if err != nil {
+		return sshmkr_templates.NewConfigError(sshmkr_templates.ErrFileAccess, "The backup location", newestBackup, "cannot be written!")
+	}
+	os.Chmod(newestBackup, origInfo.Mode().Perm())
+	os.Chtimes(newestBackup, origInfo.ModTime(), origInfo.ModTime())
+	return nil
+}
+
+// Helper method that flushes a rename in the given directory to disk
+// Not every platform supports syncing a directory, so any errors are ignored
+func syncDir(dirLoc string) {
+	dir, err := os.Open(dirLoc)
+	if err != nil {
+		return
+	}
+	dir.Sync()
+	dir.Close()
+}
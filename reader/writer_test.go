@@ -0,0 +1,59 @@
+package sshmkr_reader
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestWriteToConfigFileRotatesBackups(t *testing.T) {
+	tempDir, err := ioutil.TempDir("", "sshmkr-writer")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tempDir)
+	configLoc := filepath.Join(tempDir, "config")
+
+	// Backups left by older versions are kept without the leading dot
+	writeTestFile(t, configLoc, "version 3")
+	writeTestFile(t, filepath.Join(tempDir, "config.sshmkr-bak.1"), "version 2")
+	writeTestFile(t, filepath.Join(tempDir, "config.sshmkr-bak.2"), "version 1")
+
+	if err := WriteToConfigFile(configLoc, "version 4", 3); err != nil {
+		t.Fatal(err)
+	}
+
+	wantContents := map[string]string{
+		"config": "version 4",
+		".config.sshmkr-bak.1": "version 3",
+		".config.sshmkr-bak.2": "version 2",
+		".config.sshmkr-bak.3": "version 1",
+	}
+	for currName, currContents := range wantContents {
+		if got := readTestFile(t, filepath.Join(tempDir, currName)); got != currContents {
+			t.Errorf("%s = %q, want %q", currName, got, currContents)
+		}
+	}
+	for _, currName := range []string{"config.sshmkr-bak.1", "config.sshmkr-bak.2", ".config.sshmkr-bak.4"} {
+		if _, err := os.Stat(filepath.Join(tempDir, currName)); !os.IsNotExist(err) {
+			t.Errorf("%s should not exist after writing", currName)
+		}
+	}
+}
+
+// Helper method that writes out a file for a test
+func writeTestFile(t *testing.T, fileLoc string, fileContents string) {
+	if err := ioutil.WriteFile(fileLoc, []byte(fileContents), 0600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// Helper method that reads in a file for a test
+func readTestFile(t *testing.T, fileLoc string) string {
+	fileContents, err := ioutil.ReadFile(fileLoc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(fileContents)
+}
@@ -1,14 +1,20 @@
 package main
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"os/user"
+	"strconv"
+	"strings"
 	"flag"	
 	"sshmkr/help"
 	"sshmkr/reader"
 	"sshmkr/input"
 	"sshmkr/commands"
+	"sshmkr/templates"
+	"sshmkr/ui"
+	"github.com/kevinburke/ssh_config"
 )
 
 //// Global Variables
@@ -17,6 +23,25 @@ import (
 var helpFlagValue bool
 var versionFlagValue bool
 var configFlagValue string
+var backupsFlagValue int
+var dryRunFlagValue bool
+var confirmFlagValue bool
+
+// Flag type that collects every -set Key=Value that is passed into a subcommand
+type setFlagValues []ssh_config.KV
+
+func (values *setFlagValues) String() string {
+	return fmt.Sprint(*values)
+}
+
+func (values *setFlagValues) Set(rawValue string) error {
+	splitValue := strings.SplitN(rawValue, "=", 2)
+	if len(splitValue) != 2 || strings.TrimSpace(splitValue[0]) == "" {
+		return fmt.Errorf("expected Key=Value, got %s", rawValue)
+	}
+	*values = append(*values, ssh_config.KV{Key: strings.TrimSpace(splitValue[0]), Value: splitValue[1]})
+	return nil
+}
 
 // Initializes Program
 func init() {
@@ -35,19 +60,20 @@ func init() {
 
 	flag.StringVar(&configFlagValue, "path", defaultConfigPath, "Directory of ssh config")
 	flag.StringVar(&configFlagValue, "p", defaultConfigPath, "Directory of ssh config")
+
+	flag.IntVar(&backupsFlagValue, "backups", sshmkr_reader.DEFAULT_BACKUP_COUNT, "Number of backups of the ssh config to keep")
+
+	flag.BoolVar(&dryRunFlagValue, "dry-run", false, "Print the changes that would be made instead of writing them")
+	flag.BoolVar(&confirmFlagValue, "confirm", false, "Print the changes that would be made and ask before writing them")
 }
 
 // Main Execution of Program
 func main() {
 
-	configFile, configFileContents, configFileDecoded := sshmkr_reader.ParseConfigFile(configFlagValue)
-	configTemplateFile, _, configTemplateFileDecoded := sshmkr_reader.ParseConfigFile(fmt.Sprintf("%s_templates", configFlagValue))
-	defer configFile.Close()
-	defer configTemplateFile.Close()
-
 	// Setting up the subcommands and their flags
 	addCmd := flag.NewFlagSet("add", flag.ExitOnError)
 	addSource := addCmd.String("source", "", "Name of source template config to leverage")
+	addOptions := setInputFlags(addCmd, true)
 	sshmkr_help.SetHelpContext(addCmd, "add")
 
 	deleteCmd := flag.NewFlagSet("delete", flag.ExitOnError)
@@ -56,10 +82,13 @@ func main() {
 
 	copyCmd := flag.NewFlagSet("copy", flag.ExitOnError)
 	copySource := copyCmd.String("source", "", "Name of host config to use as basis")
+	copyOptions := setInputFlags(copyCmd, true)
 	sshmkr_help.SetHelpContext(copyCmd, "copy")
 
 	showCmd := flag.NewFlagSet("show", flag.ExitOnError)
 	showSource := showCmd.String("source", "", "Name of host config to show")
+	showOutput := showCmd.String("output", "text", "Output format of the host config: text, json or yaml")
+	showCommented := showCmd.Bool("commented", false, "Also look at hosts that are commented out")
 	sshmkr_help.SetHelpContext(showCmd, "show")
 
 	commentCmd := flag.NewFlagSet("comment", flag.ExitOnError)
@@ -68,81 +97,621 @@ func main() {
 
 	editCmd := flag.NewFlagSet("edit", flag.ExitOnError)
 	editSource := editCmd.String("source", "", "Name of host config to edit")
+	editOptions := setInputFlags(editCmd, false)
 	sshmkr_help.SetHelpContext(editCmd, "edit")
 
-	if len(os.Args) < 2 {
-		fmt.Println("Error! Expecting another argument: [add, delete, copy, show, list]")
-		os.Exit(1)
+	listCmd := flag.NewFlagSet("list", flag.ExitOnError)
+	listHeader := listCmd.String("header", "", "Only list the hosts under this main header (or \"Main/Sub\" header path)")
+	listCommented := listCmd.Bool("commented", false, "Include hosts that are commented out")
+	listSummary := listCmd.Bool("summary", false, "Show the Hostname, User and Port of each host")
+	sshmkr_help.SetHelpContext(listCmd, "list")
+
+	historyCmd := flag.NewFlagSet("history", flag.ExitOnError)
+	historyDiff := historyCmd.Bool("diff", false, "Show the changes that each command made")
+	sshmkr_help.SetHelpContext(historyCmd, "history")
+
+	undoCmd := flag.NewFlagSet("undo", flag.ExitOnError)
+	sshmkr_help.SetHelpContext(undoCmd, "undo")
+
+	resolveCmd := flag.NewFlagSet("resolve", flag.ExitOnError)
+	resolveSource := resolveCmd.String("source", "", "Hostname to resolve the options of")
+	resolveExplain := resolveCmd.Bool("explain", false, "Show where each value came from and which values were shadowed")
+	sshmkr_help.SetHelpContext(resolveCmd, "resolve")
+
+	lintCmd := flag.NewFlagSet("lint", flag.ExitOnError)
+	lintOutput := lintCmd.String("output", "text", "Format to print the problems in [text, json, yaml]")
+	sshmkr_help.SetHelpContext(lintCmd, "lint")
+
+	aliasCmd := flag.NewFlagSet("alias", flag.ExitOnError)
+	aliasSource := aliasCmd.String("source", "", "Name of host config to change the aliases of")
+	aliasName := aliasCmd.String("alias", "", "Alias to add or remove")
+	sshmkr_help.SetHelpContext(aliasCmd, "alias")
+
+	headerCmd := flag.NewFlagSet("header", flag.ExitOnError)
+	headerPath := headerCmd.String("header", "", "\"Main Header\" or \"Main Header/Sub Header\" path of the header")
+	headerName := headerCmd.String("name", "", "New name of the header when renaming it")
+	headerCascade := headerCmd.Bool("cascade", false, "Also remove the hosts that live under the header when deleting it")
+	headerBefore := headerCmd.String("before", "", "Header path to move the header in front of")
+	headerAfter := headerCmd.String("after", "", "Header path to move the header behind")
+	sshmkr_help.SetHelpContext(headerCmd, "header")
+
+	moveCmd := flag.NewFlagSet("move", flag.ExitOnError)
+	moveSource := moveCmd.String("source", "", "Name of host config to move")
+	moveTo := moveCmd.String("to", "", "\"Main Header/Sub Header\" to move the host under instead of prompting")
+	sshmkr_help.SetHelpContext(moveCmd, "move")
+
+	fmtCmd := flag.NewFlagSet("fmt", flag.ExitOnError)
+	fmtCheck := fmtCmd.Bool("check", false, "Do not write anything, and exit with 1 if the config is not formatted")
+	fmtDiff := fmtCmd.Bool("diff", false, "Do not write anything, and print out the changes that would be made")
+	sshmkr_help.SetHelpContext(fmtCmd, "fmt")
+
+	sortCmd := flag.NewFlagSet("sort", flag.ExitOnError)
+	sortHeaders := sortCmd.Bool("headers", false, "Also sort the main and sub headers")
+	sortCheck := sortCmd.Bool("check", false, "Do not write anything, and exit with 1 if the config is not sorted")
+	sortDiff := sortCmd.Bool("diff", false, "Do not write anything, and print out the changes that would be made")
+	sshmkr_help.SetHelpContext(sortCmd, "sort")
+
+	findCmd := flag.NewFlagSet("find", flag.ExitOnError)
+	findMode := findCmd.String("mode", "substring", "How the query is matched [substring, glob, regex, fuzzy]")
+	findCommented := findCmd.Bool("commented", false, "Also search hosts that are commented out")
+	sshmkr_help.SetHelpContext(findCmd, "find")
+
+	uiCmd := flag.NewFlagSet("ui", flag.ExitOnError)
+	sshmkr_help.SetHelpContext(uiCmd, "ui")
+
+	templateCmd := flag.NewFlagSet("template", flag.ExitOnError)
+	templateSource := templateCmd.String("source", "", "Name of the template to interact")
+	templateName := templateCmd.String("name", "", "Name of the new template when making one from a host")
+	templateOutput := templateCmd.String("output", "text", "Output format of the template: text, json or yaml")
+	templateOptions := &sshmkr_templates.InputOptions{}
+	templateCmd.Var((*setFlagValues)(&templateOptions.SetValues), "set", "Key=Value of the template to use instead of prompting (can be repeated)")
+	templateCmd.BoolVar(&templateOptions.AcceptDefaults, "yes", false, "Keep the value of every key that was not set instead of prompting")
+	templateCmd.BoolVar(&templateOptions.NonInteractive, "non-interactive", false, "Never prompt, and exit with an error if a value is missing")
+	sshmkr_help.SetHelpContext(templateCmd, "template")
+
+	completionCmd := flag.NewFlagSet("completion", flag.ExitOnError)
+	sshmkr_help.SetHelpContext(completionCmd, "completion")
+
+	// Every subcommand, in the order they are listed in the error messages and completion scripts
+	subcommands := []*flag.FlagSet{
+		addCmd, deleteCmd, copyCmd, showCmd, commentCmd, editCmd, listCmd, aliasCmd, headerCmd, moveCmd,
+		findCmd, resolveCmd, lintCmd, fmtCmd, sortCmd, historyCmd, undoCmd, uiCmd, templateCmd, completionCmd,
 	}
 
 	flag.Parse()
-	switch os.Args[1] {
+	if flag.NArg() < 1 {
+		if helpFlagValue == true {
+			sshmkr_help.DefaultHelp()
+		} else if versionFlagValue == true {
+			sshmkr_help.PrintVersion()
+		}
+		fmt.Printf("Error! Expecting another argument: [%s]\n", getSubcommandNames(subcommands))
+		os.Exit(1)
+	}
+
+	// The global flags are placed before the subcommand, so we only look at what comes after them
+	cmdArgs := flag.Args()
+
+	// Values typed into the prompts are remembered next to the config, so they can be brought back with the up arrow
+	inputHistoryLoc := sshmkr_reader.GetInputHistoryLoc(configFlagValue)
+	addOptions.HistoryLoc = inputHistoryLoc
+	copyOptions.HistoryLoc = inputHistoryLoc
+	editOptions.HistoryLoc = inputHistoryLoc
+	templateOptions.HistoryLoc = inputHistoryLoc
+
+	// Completion does not need the ssh_config to be readable, so it is handled before the config is read
+	switch cmdArgs[0] {
+		case "completion":
+			completionCmd.Parse(cmdArgs[1:])
+
+			exitOnError(sshmkr_commands.GenerateCompletionScript(completionCmd.Arg(0), subcommands))
+			os.Exit(0)
+		case "__complete":
+			// Hidden command that the completion scripts call to get the host names, template names and header paths
+			if len(cmdArgs) > 1 {
+				sshmkr_commands.PrintCompletionValues(cmdArgs[1], configFlagValue)
+			}
+			os.Exit(0)
+	}
+
+	// Commands that change the config hold a lock on it and the templates from before they are read until the program exits
+	if isChangingCommand(cmdArgs) && !dryRunFlagValue {
+		unlockConfig, err := lockConfig()
+		exitOnError(err)
+		defer unlockConfig()
+	}
+	configFiles, err := sshmkr_reader.ReadConfigFiles(configFlagValue)
+	exitOnError(err)
+
+	switch cmdArgs[0] {
 		case "add":
-			addCmd.Parse(os.Args[2:])
+			addCmd.Parse(cmdArgs[1:])
 
-			template := sshmkr_reader.ReadSpecificTemplate(*addSource, configTemplateFileDecoded)
-			headers := sshmkr_reader.ParseConfigHeaders(configFileContents)
-			mainHeader, subHeader := sshmkr_input.SelectNewConfigLoc(headers)
-			userAddedConfig, hostName := sshmkr_input.InterpolateUserInput(template)
-			newOutput := sshmkr_commands.AddTemplatedConfig(mainHeader, subHeader, userAddedConfig, configFileContents)
-			sshmkr_reader.WriteToConfigFile(configFlagValue, newOutput)
+			configTemplateFiles, err := sshmkr_reader.ReadTemplateFiles(configFlagValue, false)
+			exitOnError(err)
+			sources, hostOptions := getHostOptions(*addSource, *addOptions)
+			hostNames := []string{}
+			for currIndex, currOptions := range hostOptions {
+				// Without a source, the user picks the template instead of getting whichever one is first
+				if len(sources[currIndex]) <= 0 {
+					sources[currIndex], err = sshmkr_input.SelectTemplate(sshmkr_reader.GetTemplateNames(configTemplateFiles), currOptions)
+					exitOnError(err)
+				}
+				template, err := sshmkr_reader.ReadAnnotatedTemplate(sources[currIndex], configTemplateFiles)
+				exitOnError(err)
+				printTemplateSource(sources[currIndex])
+				hostNames = append(hostNames, addTemplatedHost(template, currOptions, configFiles))
+			}
+			exitOnError(saveConfig(cmdArgs, configFiles))
 
-			fmt.Println("Sucessfully added host", hostName , "to config!")
+			for _, hostName := range hostNames {
+				fmt.Println("Sucessfully added host", hostName , "to config!")
+			}
 		case "delete":
-			deleteCmd.Parse(os.Args[2:])
+			deleteCmd.Parse(cmdArgs[1:])
+
+			exitOnError(sshmkr_commands.RemoveHostConfig(*deleteSource, configFiles))
+			exitOnError(saveConfig(cmdArgs, configFiles))
 
-			newOutput := sshmkr_commands.RemoveHostConfig(*deleteSource, configFileContents)
-			sshmkr_reader.WriteToConfigFile(configFlagValue, newOutput)
-			
 			fmt.Println("Sucessfully removed host", *deleteSource ,"from ssh_config!")
 		case "copy":
-			copyCmd.Parse(os.Args[2:])
+			copyCmd.Parse(cmdArgs[1:])
 
-			template := sshmkr_reader.ReadSpecificTemplate(*copySource, configFileDecoded)
-			headers := sshmkr_reader.ParseConfigHeaders(configFileContents)
-			mainHeader, subHeader := sshmkr_input.SelectNewConfigLoc(headers)
-			userAddedConfig, hostName := sshmkr_input.InterpolateUserInput(template)
-			newOutput := sshmkr_commands.AddTemplatedConfig(mainHeader, subHeader, userAddedConfig, configFileContents)
-			sshmkr_reader.WriteToConfigFile(configFlagValue, newOutput)			
+			sources, hostOptions := getHostOptions(*copySource, *copyOptions)
+			hostNames := []string{}
+			for currIndex, currOptions := range hostOptions {
+				template, err := sshmkr_reader.ReadSpecificTemplate(sources[currIndex], configFiles)
+				exitOnError(err)
+				printTemplateSource(sources[currIndex])
+				hostNames = append(hostNames, addTemplatedHost(template, currOptions, configFiles))
+			}
+			exitOnError(saveConfig(cmdArgs, configFiles))
 
-			fmt.Println("Sucessfuly created new host", hostName, "from template!")
+			for _, hostName := range hostNames {
+				fmt.Println("Sucessfuly created new host", hostName, "from template!")
+			}
 		case "show":
-			showCmd.Parse(os.Args[2:])
+			showCmd.Parse(cmdArgs[1:])
 
-			sshmkr_commands.GetSpecificHostConfig(*showSource, configFileDecoded)
+			exitOnError(sshmkr_commands.GetSpecificHostConfig(*showSource, *showCommented, *showOutput, configFiles))
 		case "comment":
-			commentCmd.Parse(os.Args[2:])
+			commentCmd.Parse(cmdArgs[1:])
+
+			hasCommented, err := sshmkr_commands.CommentHostConfig(*commentSource, configFiles)
+			exitOnError(err)
+			exitOnError(saveConfig(cmdArgs, configFiles))
 
-			newOutput, hasCommented := sshmkr_commands.CommentHostConfig(*commentSource, configFileContents)
-			sshmkr_reader.WriteToConfigFile(configFlagValue, newOutput)
-			
 			if hasCommented {
 				fmt.Println("Sucessfully commented out host", *commentSource, "!")
 			} else {
 				fmt.Println("Sucessfully uncommented out host", *commentSource, "!")
 			}
 		case "edit":
-			editCmd.Parse(os.Args[2:])
+			editCmd.Parse(cmdArgs[1:])
 
-			template := sshmkr_reader.ReadSpecificTemplate(*editSource, configFileDecoded)
-			editedConfig, newHostName := sshmkr_input.InterpolateUserInput(template)			
-			newOutput := sshmkr_commands.EditExisingConfig(*editSource, editedConfig, configFileContents)
-			sshmkr_reader.WriteToConfigFile(configFlagValue, newOutput)
+			sources, hostOptions := getHostOptions(*editSource, *editOptions)
+			newHostNames := []string{}
+			for currIndex, currOptions := range hostOptions {
+				template, err := sshmkr_reader.ReadSpecificTemplate(sources[currIndex], configFiles)
+				exitOnError(err)
+				printTemplateSource(sources[currIndex])
+				editedConfig, newHostName, err := sshmkr_input.InterpolateUserInput(template, currOptions)
+				exitOnError(err)
+				exitOnError(sshmkr_commands.EditExisingConfig(sources[currIndex], editedConfig, configFiles))
+				newHostNames = append(newHostNames, newHostName)
+			}
+			exitOnError(saveConfig(cmdArgs, configFiles))
 
-			if newHostName != *editSource {
-				fmt.Println("Sucesfully edited and renamed host config,", *editSource, ",to", newHostName, "!")
-			} else {
-				fmt.Println("Sucesfully edited host config,", *editSource, "!")
+			for currIndex, currSource := range sources {
+				if _, hostIndex, _ := configFiles.FindBlock(currSource, false); hostIndex == -1 {
+					fmt.Println("Sucesfully edited and renamed host config,", currSource, ",to", newHostNames[currIndex], "!")
+				} else {
+					fmt.Println("Sucesfully edited host config,", currSource, "!")
+				}
 			}
+		case "move":
+			moveCmd.Parse(cmdArgs[1:])
 
-		default:
-			if helpFlagValue == true {
-				sshmkr_help.DefaultHelp()
-			} else if versionFlagValue == true {
-				sshmkr_help.PrintVersion()
-			} else {
-				fmt.Printf("Subcommand '%s' invalid. Available commands are: [add, delete, copy, show, list]\n", os.Args[1])
+			// The hostname can be passed in either as a flag or as the argument after the flags
+			if moveCmd.NArg() > 0 {
+				*moveSource = moveCmd.Arg(0)
+				moveCmd.Parse(moveCmd.Args()[1:])
+			}
+			// The host is checked before the user is asked where to move it
+			_, _, _, err := sshmkr_commands.FindSourceHost(*moveSource, false, "move", configFiles)
+			exitOnError(err)
+
+			headers := sshmkr_reader.ParseConfigHeaders(configFiles)
+			mainHeader, subHeader, err := sshmkr_input.SelectNewConfigLoc(headers, sshmkr_templates.InputOptions{HeaderPath: *moveTo})
+			exitOnError(err)
+			exitOnError(sshmkr_commands.MoveHostConfig(*moveSource, mainHeader, subHeader, configFiles))
+			exitOnError(saveConfig(cmdArgs, configFiles))
+
+			fmt.Println("Sucessfully moved host", *moveSource, "under", sshmkr_reader.TrimHeaderIndicator(mainHeader) + "/" + sshmkr_reader.TrimHeaderIndicator(subHeader), "!")
+		case "list":
+			listCmd.Parse(cmdArgs[1:])
+
+			headers := sshmkr_reader.ParseConfigTree(configFiles)
+			exitOnError(sshmkr_commands.ListConfigTree(*listHeader, *listCommented, *listSummary, headers))
+		case "alias":
+			if len(cmdArgs) < 2 {
+				fmt.Println("Error! Expecting another argument: [add, remove]")
+				os.Exit(1)
+			}
+			aliasCmd.Parse(cmdArgs[2:])
+
+			switch cmdArgs[1] {
+				case "add":
+					exitOnError(sshmkr_commands.AddHostAlias(*aliasSource, *aliasName, configFiles))
+					exitOnError(saveConfig(cmdArgs, configFiles))
+					fmt.Println("Sucessfully added alias", *aliasName, "to host", *aliasSource, "!")
+				case "remove":
+					exitOnError(sshmkr_commands.RemoveHostAlias(*aliasSource, *aliasName, configFiles))
+					exitOnError(saveConfig(cmdArgs, configFiles))
+					fmt.Println("Sucessfully removed alias", *aliasName, "from host", *aliasSource, "!")
+				default:
+					fmt.Printf("Alias command '%s' invalid. Available commands are: [add, remove]\n", cmdArgs[1])
+					os.Exit(1)
+			}
+		case "header":
+			if len(cmdArgs) < 2 {
+				fmt.Println("Error! Expecting another argument: [add, rename, delete, move]")
+				os.Exit(1)
+			}
+			headerCmd.Parse(cmdArgs[2:])
+
+			switch cmdArgs[1] {
+				case "add":
+					exitOnError(sshmkr_commands.AddHeader(*headerPath, configFiles))
+					exitOnError(saveConfig(cmdArgs, configFiles))
+					fmt.Println("Sucessfully added header", *headerPath, "!")
+				case "rename":
+					exitOnError(sshmkr_commands.RenameHeader(*headerPath, *headerName, configFiles))
+					exitOnError(saveConfig(cmdArgs, configFiles))
+					fmt.Println("Sucessfully renamed header", *headerPath, "to", *headerName, "!")
+				case "delete":
+					removedHosts, err := sshmkr_commands.RemoveHeader(*headerPath, *headerCascade, configFiles)
+					exitOnError(err)
+					exitOnError(saveConfig(cmdArgs, configFiles))
+					fmt.Println("Sucessfully removed header", *headerPath, "along with", removedHosts, "host(s) under it!")
+				case "move":
+					exitOnError(sshmkr_commands.MoveHeader(*headerPath, *headerBefore, *headerAfter, configFiles))
+					exitOnError(saveConfig(cmdArgs, configFiles))
+					fmt.Println("Sucessfully moved header", *headerPath, "!")
+				default:
+					fmt.Printf("Header command '%s' invalid. Available commands are: [add, rename, delete, move]\n", cmdArgs[1])
+					os.Exit(1)
+			}
+		case "template":
+			if len(cmdArgs) < 2 {
+				fmt.Println("Error! Expecting another argument: [list, show, add, edit, delete, from-host]")
+				os.Exit(1)
+			}
+			templateCmd.Parse(cmdArgs[2:])
+
+			// The template (or the host for from-host) can be passed in either as a flag or as the argument after the flags
+			templateArg := ""
+			if templateCmd.NArg() > 0 {
+				templateArg = templateCmd.Arg(0)
+				templateCmd.Parse(templateCmd.Args()[1:])
+			}
+			if templateArg != "" && cmdArgs[1] != "from-host" {
+				*templateSource = templateArg
+			}
+
+			// Adding a template creates the templates file if it does not exist yet
+			configTemplateFiles, err := sshmkr_reader.ReadTemplateFiles(configFlagValue, cmdArgs[1] == "add" || cmdArgs[1] == "from-host")
+			exitOnError(err)
+
+			switch cmdArgs[1] {
+				case "list":
+					sshmkr_commands.ListTemplates(configTemplateFiles)
+				case "show":
+					exitOnError(sshmkr_commands.ShowTemplate(*templateSource, *templateOutput, configTemplateFiles))
+				case "add":
+					newOptions := templateOptions.SetValues
+					if len(newOptions) == 0 && !templateOptions.NonInteractive && len(*templateSource) > 0 {
+						newOptions, err = sshmkr_input.ReadTemplateOptions()
+						exitOnError(err)
+					}
+					exitOnError(sshmkr_commands.AddTemplate(*templateSource, newOptions, configTemplateFiles))
+					exitOnError(saveConfig(cmdArgs, configTemplateFiles))
+					fmt.Println("Sucessfully added template", *templateSource, "!")
+				case "edit":
+					template, err := sshmkr_reader.ReadSpecificTemplate(*templateSource, configTemplateFiles)
+					exitOnError(err)
+					editedTemplate, _, err := sshmkr_input.InterpolateUserInput(template, *templateOptions)
+					exitOnError(err)
+					exitOnError(sshmkr_commands.EditTemplate(*templateSource, editedTemplate, configTemplateFiles))
+					exitOnError(saveConfig(cmdArgs, configTemplateFiles))
+					fmt.Println("Sucessfully edited template", *templateSource, "!")
+				case "delete":
+					exitOnError(sshmkr_commands.RemoveTemplate(*templateSource, configTemplateFiles))
+					exitOnError(saveConfig(cmdArgs, configTemplateFiles))
+					fmt.Println("Sucessfully removed template", *templateSource, "from config_templates!")
+				case "from-host":
+					newTemplateName, err := sshmkr_commands.AddTemplateFromHost(templateArg, *templateName, configFiles, configTemplateFiles)
+					exitOnError(err)
+					exitOnError(saveConfig(cmdArgs, configTemplateFiles))
+					fmt.Println("Sucessfully created template", newTemplateName, "from host", templateArg, "!")
+				default:
+					fmt.Printf("Template command '%s' invalid. Available commands are: [list, show, add, edit, delete, from-host]\n", cmdArgs[1])
+					os.Exit(1)
+			}
+		case "fmt":
+			fmtCmd.Parse(cmdArgs[1:])
+
+			sshmkr_commands.FormatConfig(configFiles)
+			checkOrSaveConfig(cmdArgs, configFiles, *fmtCheck, *fmtDiff, "formatted")
+		case "sort":
+			sortCmd.Parse(cmdArgs[1:])
+
+			// The warnings go to stderr, so they do not end up in the middle of the -diff output
+			for _, currWarning := range sshmkr_commands.SortConfig(*sortHeaders, configFiles) {
+				fmt.Fprintln(os.Stderr, currWarning)
+			}
+			checkOrSaveConfig(cmdArgs, configFiles, *sortCheck, *sortDiff, "sorted")
+		case "find":
+			findCmd.Parse(cmdArgs[1:])
+
+			// The query can be passed in either before or after the flags
+			findQuery := ""
+			if findCmd.NArg() > 0 {
+				findQuery = findCmd.Arg(0)
+				findCmd.Parse(findCmd.Args()[1:])
+			}
+			foundCount, err := sshmkr_commands.FindHosts(findQuery, *findMode, *findCommented, configFiles)
+			exitOnError(err)
+			if foundCount == 0 {
 				os.Exit(1)
 			}
+		case "ui":
+			uiCmd.Parse(cmdArgs[1:])
+			checkNoPreviewFlags("ui")
+
+			// The interface stays open for as long as the user wants, so it only holds the lock while each change is saved
+			// Changes made to the config while it is open are caught by the check for outside changes instead
+			exitOnError(sshmkr_ui.RunUI(configFlagValue, func(cmdArgs []string, files *sshmkr_templates.ConfigFiles) error {
+				unlockConfig, err := lockConfig()
+				if err != nil {
+					return err
+				}
+				defer unlockConfig()
+				return saveConfig(cmdArgs, files)
+			}))
+		case "resolve":
+			resolveCmd.Parse(cmdArgs[1:])
+
+			// The hostname can be passed in either as a flag or as the argument after the flags
+			if resolveCmd.NArg() > 0 {
+				*resolveSource = resolveCmd.Arg(0)
+				resolveCmd.Parse(resolveCmd.Args()[1:])
+			}
+			exitOnError(sshmkr_commands.ResolveHostConfig(*resolveSource, *resolveExplain, configFiles))
+		case "lint":
+			lintCmd.Parse(cmdArgs[1:])
+
+			issueCount, err := sshmkr_commands.LintConfig(*lintOutput, configFiles)
+			exitOnError(err)
+			if issueCount > 0 {
+				os.Exit(1)
+			}
+		case "history":
+			historyCmd.Parse(cmdArgs[1:])
+
+			entries, err := sshmkr_reader.ReadJournal(configFlagValue)
+			exitOnError(err)
+			sshmkr_commands.PrintJournalHistory(entries, *historyDiff)
+		case "undo":
+			undoCmd.Parse(cmdArgs[1:])
+
+			undoCount := 1
+			if undoCmd.NArg() > 0 {
+				parsedCount, err := strconv.Atoi(undoCmd.Arg(0))
+				if err != nil {
+					fmt.Println("Error!", undoCmd.Arg(0), "is not a valid number of changes to undo!")
+					os.Exit(1)
+				}
+				undoCount = parsedCount
+			}
+			// The undo is previewed by diffing each file against what the journal would revert it to
+			if dryRunFlagValue || confirmFlagValue {
+				undoFiles, err := sshmkr_commands.GetUndoChanges(configFlagValue, undoCount)
+				exitOnError(err)
+				previewChanges(undoFiles)
+			}
+			exitOnError(sshmkr_commands.UndoJournalEntries(configFlagValue, undoCount, backupsFlagValue))
+		default:
+			fmt.Printf("Subcommand '%s' invalid. Available commands are: [%s]\n", cmdArgs[0], getSubcommandNames(subcommands))
+			os.Exit(1)
+	}
+}
+
+// Helper method that joins the names of the subcommands into a comma separated list for the error messages
+func getSubcommandNames(subcommands []*flag.FlagSet) string {
+	subcommandNames := []string{}
+	for _, currCmd := range subcommands {
+		subcommandNames = append(subcommandNames, currCmd.Name())
+	}
+	return strings.Join(subcommandNames, ", ")
+}
+
+// Helper method that gets the source and options of every host that add, copy or edit should make or change
+// Without an answers file there is just one host, which uses the source and options that were passed in
+func getHostOptions(source string, options sshmkr_templates.InputOptions) ([]string, []sshmkr_templates.InputOptions) {
+	if options.AnswersLoc == "" {
+		return []string{source}, []sshmkr_templates.InputOptions{options}
+	}
+
+	allAnswers, err := sshmkr_reader.ReadAnswersFile(options.AnswersLoc)
+	exitOnError(err)
+
+	sources := []string{}
+	hostOptions := []sshmkr_templates.InputOptions{}
+	for _, currAnswers := range allAnswers {
+		if currAnswers.Source != "" {
+			sources = append(sources, currAnswers.Source)
+		} else {
+			sources = append(sources, source)
+		}
+		hostOptions = append(hostOptions, currAnswers.ApplyTo(options))
+	}
+	return sources, hostOptions
+}
+
+// Helper method that asks where to place a new host and fills in its template, then adds it to the config
+// Returns the name of the new host
+func addTemplatedHost(template sshmkr_templates.ConfigTemplate, options sshmkr_templates.InputOptions, configFiles *sshmkr_templates.ConfigFiles) string {
+	headers := sshmkr_reader.ParseConfigHeaders(configFiles)
+	mainHeader, subHeader, err := sshmkr_input.SelectNewConfigLoc(headers, options)
+	exitOnError(err)
+
+	// The header that was picked is what the {{.Header}} variables of the template are filled in with
+	options.HeaderPath = sshmkr_reader.TrimHeaderIndicator(mainHeader) + "/" + sshmkr_reader.TrimHeaderIndicator(subHeader)
+	userAddedConfig, hostName, err := sshmkr_input.InterpolateUserInput(template, options)
+	exitOnError(err)
+	exitOnError(sshmkr_commands.AddTemplatedConfig(mainHeader, subHeader, userAddedConfig, configFiles))
+	return hostName
+}
+
+// Helper method that prints out which template a host config is being made from
+func printTemplateSource(templateName string) {
+	fmt.Println("Found host config to use for template,", templateName, "...")
+	fmt.Println("")
+}
+
+// Writes out every config file that was changed and records the changes in the journal
+// With -dry-run the changes are only printed out, and with -confirm they are printed out and the user is asked before writing
+func saveConfig(cmdArgs []string, configFiles *sshmkr_templates.ConfigFiles) error {
+	previewChanges(configFiles)
+
+	changedDocs := configFiles.GetChangedDocs()
+	if err := sshmkr_reader.CheckForOutsideChanges(changedDocs); err != nil {
+		return err
+	}
+	for _, currDoc := range changedDocs {
+		if err := sshmkr_reader.WriteToConfigFile(currDoc.Path, currDoc.String(), backupsFlagValue); err != nil {
+			return err
+		}
+	}
+	if err := sshmkr_reader.RecordJournalEntry(configFlagValue, cmdArgs, changedDocs); err != nil {
+		return err
+	}
+	// The typed in values are only remembered once the changes they were typed in for are written
+	sshmkr_input.SavePendingHistory()
+	return nil
+}
+
+// Helper method that prints out the diff of the changes with -dry-run or -confirm
+// Exits without writing anything with -dry-run, or if the changes were not confirmed
+func previewChanges(configFiles *sshmkr_templates.ConfigFiles) {
+	if dryRunFlagValue || confirmFlagValue {
+		hasChanges := sshmkr_commands.PreviewChanges(configFiles, true)
+		if dryRunFlagValue {
+			if !hasChanges {
+				fmt.Println("No changes would be made to the ssh_config!")
+			}
+			os.Exit(0)
+		} else if hasChanges && !sshmkr_input.AskForConfirmation("Write these changes to the ssh_config?") {
+			fmt.Println("Nothing was written to the ssh_config!")
+			os.Exit(0)
+		}
+	}
+}
+
+// Writes out the changes of a command that rewrites the whole config, like fmt and sort
+// With check or showDiff nothing is written, and check exits with 1 if anything would be changed
+func checkOrSaveConfig(cmdArgs []string, configFiles *sshmkr_templates.ConfigFiles, check bool, showDiff bool, doneState string) {
+	if check || showDiff {
+		hasChanges := sshmkr_commands.PreviewChanges(configFiles, showDiff)
+		if check && hasChanges {
+			os.Exit(1)
+		} else if !hasChanges {
+			fmt.Println("The ssh_config is already", doneState, "!")
+		}
+		return
+	}
+
+	if len(configFiles.GetChangedDocs()) == 0 {
+		fmt.Println("The ssh_config is already", doneState, "!")
+		return
+	}
+	exitOnError(saveConfig(cmdArgs, configFiles))
+	fmt.Println("Sucessfully", doneState, "the ssh_config!")
+}
+
+// Helper method that prints out an error and exits with the code for its kind of error
+// Errors from reading or writing files and aborted prompts exit with 1, and errors from what was passed in exit with -1
+func exitOnError(err error) {
+	if err == nil {
+		return
+	}
+
+	if errors.Is(err, sshmkr_templates.ErrFileAccess) || errors.Is(err, sshmkr_templates.ErrConcurrentChange) {
+		fmt.Println("Error!", err)
+		os.Exit(1)
+	} else if errors.Is(err, sshmkr_templates.ErrAborted) {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	fmt.Println(err)
+	os.Exit(-1)
+}
+
+// Sets up the flags that let add, copy and edit run without prompting
+// Returns the options that will be filled in once the subcommand is parsed
+func setInputFlags(cmd *flag.FlagSet, withHeader bool) *sshmkr_templates.InputOptions {
+	options := &sshmkr_templates.InputOptions{}
+
+	cmd.Var((*setFlagValues)(&options.SetValues), "set", "Key=Value to use for a template key instead of prompting (can be repeated)")
+	if withHeader {
+		cmd.StringVar(&options.HeaderPath, "header", "", "\"Main Header/Sub Header\" to place the new host under")
+	}
+	cmd.BoolVar(&options.AcceptDefaults, "yes", false, "Use the default value of every key that was not set instead of prompting")
+	cmd.BoolVar(&options.NonInteractive, "non-interactive", false, "Never prompt, and exit with an error if a value is missing")
+	cmd.StringVar(&options.AnswersLoc, "answers", "", "YAML file with the header and values of one or more hosts")
+	return options
+}
+
+// Helper method that checks if a subcommand can change the ssh_config
+func isChangingCommand(cmdArgs []string) bool {
+	switch cmdArgs[0] {
+		case "add", "delete", "copy", "comment", "edit", "alias", "header", "move", "fmt", "sort", "undo":
+			return true
+		case "template":
+			// Listing and showing the templates only reads them
+			if len(cmdArgs) > 1 {
+				switch cmdArgs[1] {
+					case "add", "edit", "delete", "from-host":
+						return true
+				}
+			}
 	}
-}
\ No newline at end of file
+	return false
+}
+
+// Helper method that waits for and takes the locks on the config and its templates
+// Returns the function that releases both of them
+func lockConfig() (func(), error) {
+	unlockConfig, err := sshmkr_reader.LockConfigFile(configFlagValue)
+	if err != nil {
+		return nil, err
+	}
+	unlockTemplates, err := sshmkr_reader.LockConfigFile(sshmkr_reader.GetTemplatesLoc(configFlagValue))
+	if err != nil {
+		unlockConfig()
+		return nil, err
+	}
+	return func() {
+		unlockTemplates()
+		unlockConfig()
+	}, nil
+}
+
+// Helper method that exits if -dry-run or -confirm was passed into a command that does not support them
+func checkNoPreviewFlags(cmdName string) {
+	if dryRunFlagValue || confirmFlagValue {
+		fmt.Println("Error! The -dry-run and -confirm flags cannot be used with", cmdName, "!")
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,285 @@
+package sshmkr_templates
+
+import (
+	"strings"
+)
+
+// The different kinds of lines that can be found in a ssh config
+type LineKind int
+
+const (
+	BlankLine LineKind = iota
+	CommentLine
+	MainHeaderLine
+	SubHeaderLine
+	HostLine
+	MatchLine
+	OptionLine
+)
+
+// Constants
+const MATCH_PREFIX = "Match "
+
+// Data struct that holds a single line of a ssh config
+// Raw is always the exact text of the line, so untouched lines are written back as they were read
+type ConfigLine struct {
+	Raw string
+	Kind LineKind
+	Commented bool		// Host/Match/Option lines that have been commented out with a #
+	Indent string		// Whitespace placed before the key
+	Key string
+	Separator string	// Text placed between the key and value, i.e " " or "="
+	Value string
+	Comment string		// Inline comment after the value, including its leading whitespace
+}
+
+// Data struct that holds a logical piece of a ssh config
+// Host and Match blocks hold all of their options in Lines, with the Host/Match line first
+// Every other entry (headers, comments, blank lines, global options) holds a single line
+type ConfigEntry struct {
+	Kind LineKind
+	Comments []*ConfigLine	// Comment lines placed directly above a Host/Match block
+	Lines []*ConfigLine
+}
+
+// Data struct that holds a fully parsed ssh config file
+type ConfigDocument struct {
+	Path string				// Location of the file the document was read from
+	OrigContents string		// Contents of the file when it was read, used to tell if the document changed
+	Entries []*ConfigEntry
+}
+
+// Rebuilds the raw text of a line from its parsed fields
+func (line *ConfigLine) render() {
+	commentInd := ""
+	if line.Commented {
+		commentInd = "#"
+	}
+	line.Raw = commentInd + line.Indent + line.Key + line.Separator + line.Value + line.Comment
+}
+
+// Changes the value of a line, keeping its indentation and inline comment
+func (line *ConfigLine) SetValue(value string) {
+	if line.Separator == "" {
+		line.Separator = " "
+	}
+	line.Value = value
+	line.render()
+}
+
+// Rewrites a line with the given indentation and key
+// A single space is placed between the key and value, and before the inline comment
+func (line *ConfigLine) Reformat(indent string, key string) {
+	line.Indent = indent
+	line.Key = key
+	line.Separator = ""
+	if line.Value != "" {
+		line.Separator = " "
+	}
+	if strings.TrimSpace(line.Comment) != "" {
+		line.Comment = " " + strings.TrimSpace(line.Comment)
+	}
+	line.render()
+}
+
+// Comments in/out a line by adding/removing a leading #
+func (line *ConfigLine) SetCommented(commented bool) {
+	if line.Commented == commented {
+		return
+	}
+
+	line.Commented = commented
+	if commented {
+		line.Raw = "#" + line.Raw
+	} else {
+		commentIndex := strings.Index(line.Raw, "#")
+		line.Raw = line.Raw[:commentIndex] + line.Raw[commentIndex+1:]
+	}
+}
+
+// Checks if the entry is a Host or Match block
+func (entry *ConfigEntry) IsBlock() bool {
+	return entry.Kind == HostLine || entry.Kind == MatchLine
+}
+
+// Checks if the entry has been commented out
+func (entry *ConfigEntry) IsCommented() bool {
+	return entry.Lines[0].Commented
+}
+
+// Gets the Host/Match line of a block
+func (entry *ConfigEntry) GetHeaderLine() *ConfigLine {
+	return entry.Lines[0]
+}
+
+// Gets the name of a block, which is its first pattern for a Host block
+// Match blocks are named after their criteria, i.e "Match user deploy"
+func (entry *ConfigEntry) GetName() string {
+	if entry.Kind == MatchLine {
+		return MATCH_PREFIX + strings.Join(strings.Fields(entry.GetHeaderLine().Value), " ")
+	}
+
+	patterns := entry.GetPatterns()
+	if len(patterns) == 0 {
+		return ""
+	}
+	return patterns[0]
+}
+
+// Gets all of the patterns that are listed on a Host line
+func (entry *ConfigEntry) GetPatterns() []string {
+	return strings.Fields(entry.Lines[0].Value)
+}
+
+// Checks if the block can be referred to by the given name
+// Host blocks go by any of their patterns, and Match blocks go by "Match <criteria>"
+func (entry *ConfigEntry) IsNamed(name string) bool {
+	if entry.Kind == MatchLine {
+		return strings.EqualFold(entry.GetName(), strings.Join(strings.Fields(name), " "))
+	}
+	return entry.Kind == HostLine && entry.HasPattern(name)
+}
+
+// Checks if any of the patterns on a Host line is exactly the given name
+// Negated patterns (i.e !name) never match, since they exclude that name from the block
+func (entry *ConfigEntry) HasPattern(name string) bool {
+	for _, currPattern := range entry.GetPatterns() {
+		if currPattern == name && !strings.HasPrefix(currPattern, "!") {
+			return true
+		}
+	}
+	return false
+}
+
+// Replaces all of the patterns on a Host line
+func (entry *ConfigEntry) SetPatterns(patterns []string) {
+	entry.GetHeaderLine().SetValue(strings.Join(patterns, " "))
+}
+
+// Gets all of the option lines of a block, ignoring the ones that do not share the block's commented state
+func (entry *ConfigEntry) GetOptions() []*ConfigLine {
+	options := []*ConfigLine{}
+	for _, currLine := range entry.Lines[1:] {
+		if currLine.Kind == OptionLine && currLine.Commented == entry.IsCommented() {
+			options = append(options, currLine)
+		}
+	}
+	return options
+}
+
+// Gets the value of the first option in a block that matches the given key
+func (entry *ConfigEntry) GetOption(key string) string {
+	for _, currLine := range entry.GetOptions() {
+		if strings.EqualFold(currLine.Key, key) {
+			return currLine.Value
+		}
+	}
+	return ""
+}
+
+// Gets every line of the entry, including the comments attached to it
+func (entry *ConfigEntry) GetAllLines() []*ConfigLine {
+	allLines := make([]*ConfigLine, 0, len(entry.Comments) + len(entry.Lines))
+	allLines = append(allLines, entry.Comments...)
+	return append(allLines, entry.Lines...)
+}
+
+// Serializes the document back into the text of a ssh config
+func (doc *ConfigDocument) String() string {
+	rawLines := []string{}
+	for _, currEntry := range doc.Entries {
+		for _, currLine := range currEntry.GetAllLines() {
+			rawLines = append(rawLines, currLine.Raw)
+		}
+	}
+	return strings.Join(rawLines, "\n")
+}
+
+// Checks if the document was changed since it was read
+func (doc *ConfigDocument) HasChanged() bool {
+	return doc.String() != doc.OrigContents
+}
+
+// Gets the main and sub header that the entry at the given index lives under
+func (doc *ConfigDocument) GetHeadersOf(index int) (string, string) {
+	mainHeader := ""
+	subHeader := ""
+	for _, currEntry := range doc.Entries[:index] {
+		if currEntry.Kind == MainHeaderLine {
+			mainHeader = currEntry.Lines[0].Raw
+			subHeader = ""
+		} else if currEntry.Kind == SubHeaderLine && mainHeader != "" {
+			subHeader = currEntry.Lines[0].Raw
+		}
+	}
+	return mainHeader, subHeader
+}
+
+// Finds the index of the given sub header that lives under the given main header
+// Returns -1 if the headers do not exist
+func (doc *ConfigDocument) FindSubHeader(mainHeader string, subHeader string) int {
+	foundMainHeader := false
+	for currIndex, currEntry := range doc.Entries {
+		if currEntry.Kind == MainHeaderLine {
+			if foundMainHeader {
+				break
+			}
+			foundMainHeader = strings.TrimSpace(currEntry.Lines[0].Raw) == strings.TrimSpace(mainHeader)
+		} else if foundMainHeader && currEntry.Kind == SubHeaderLine && strings.TrimSpace(currEntry.Lines[0].Raw) == strings.TrimSpace(subHeader) {
+			return currIndex
+		}
+	}
+	return -1
+}
+
+// Finds where the section that starts at the given header index ends
+// This is the index of the next header, or the end of the document
+func (doc *ConfigDocument) FindSectionEnd(headerIndex int) int {
+	for currIndex := headerIndex + 1; currIndex < len(doc.Entries); currIndex = currIndex + 1 {
+		currKind := doc.Entries[currIndex].Kind
+		if currKind == MainHeaderLine || currKind == SubHeaderLine {
+			return currIndex
+		}
+	}
+	return len(doc.Entries)
+}
+
+// Finds where the section of the main header at the given index ends
+// This is the index of the next main header, or the end of the document
+func (doc *ConfigDocument) FindMainSectionEnd(mainIndex int) int {
+	for currIndex := mainIndex + 1; currIndex < len(doc.Entries); currIndex = currIndex + 1 {
+		if doc.Entries[currIndex].Kind == MainHeaderLine {
+			return currIndex
+		}
+	}
+	return len(doc.Entries)
+}
+
+// Inserts the given entries into the document at the given index
+func (doc *ConfigDocument) InsertEntries(index int, entries ...*ConfigEntry) {
+	newEntries := make([]*ConfigEntry, 0, len(doc.Entries) + len(entries))
+	newEntries = append(newEntries, doc.Entries[:index]...)
+	newEntries = append(newEntries, entries...)
+	doc.Entries = append(newEntries, doc.Entries[index:]...)
+}
+
+// Removes the entry at the given index from the document
+func (doc *ConfigDocument) RemoveEntry(index int) {
+	doc.Entries = append(doc.Entries[:index], doc.Entries[index+1:]...)
+}
+
+// Creates a new entry that holds a single blank line
+func NewBlankEntry() *ConfigEntry {
+	return &ConfigEntry{Kind: BlankLine, Lines: []*ConfigLine{&ConfigLine{Kind: BlankLine}}}
+}
+
+// Gets the line numbers of the first and last line of the entry at the given index
+// Comments attached to the entry are not counted, and the line numbers start at 1
+func (doc *ConfigDocument) GetLineRange(index int) (int, int) {
+	startLine := 1
+	for _, currEntry := range doc.Entries[:index] {
+		startLine = startLine + len(currEntry.Comments) + len(currEntry.Lines)
+	}
+	startLine = startLine + len(doc.Entries[index].Comments)
+	return startLine, startLine + len(doc.Entries[index].Lines) - 1
+}
@@ -0,0 +1,38 @@
+package sshmkr_templates
+
+import (
+	"errors"
+	"fmt"
+)
+
+// The kinds of errors that can happen, which a ConfigError can be matched against with errors.Is
+var ErrHostNotFound = errors.New("host not found")
+var ErrTemplateNotFound = errors.New("template not found")
+var ErrHeaderNotFound = errors.New("header not found")
+var ErrDuplicateHost = errors.New("duplicate host")
+var ErrInvalidInput = errors.New("invalid input")
+var ErrFileAccess = errors.New("file cannot be read or written")
+var ErrConcurrentChange = errors.New("config is being changed by something else")
+var ErrAborted = errors.New("aborted by the user")
+
+// Data struct that holds an error along with the kind of error it is
+// The message is what gets shown to the user, so it reads the same way the program's other output does
+type ConfigError struct {
+	Kind error
+	Message string
+}
+
+func (err *ConfigError) Error() string {
+	return err.Message
+}
+
+// Lets errors.Is match the error against its kind, i.e errors.Is(err, ErrHostNotFound)
+func (err *ConfigError) Unwrap() error {
+	return err.Kind
+}
+
+// Creates a ConfigError of the given kind, with the message formatted the same way as fmt.Sprintln
+func NewConfigError(kind error, messageParts ...interface{}) error {
+	message := fmt.Sprintln(messageParts...)
+	return &ConfigError{Kind: kind, Message: message[:len(message)-1]}
+}
@@ -0,0 +1,97 @@
+package sshmkr_templates
+
+import (
+	"strings"
+)
+
+// Data struct that holds a ssh config along with every file that it pulls in with Include
+type ConfigFiles struct {
+	Docs []*ConfigDocument						// The main config is always first, followed by included files in the order they were found
+	Includes map[*ConfigLine][]*ConfigDocument	// The files that were pulled in by each Include line
+}
+
+// Gets the document of the main config file
+func (files *ConfigFiles) GetMainDoc() *ConfigDocument {
+	return files.Docs[0]
+}
+
+// Goes through every entry of every file in the order that ssh reads them
+// Included files are gone through right after the entry that includes them
+// The walk stops early if the callback returns false
+func (files *ConfigFiles) WalkEntries(callback func(doc *ConfigDocument, index int) bool) {
+	files.walkDoc(files.GetMainDoc(), callback)
+}
+
+// Helper method that walks a single document, going into any files it includes
+// Returns false if the walk was stopped early
+func (files *ConfigFiles) walkDoc(doc *ConfigDocument, callback func(doc *ConfigDocument, index int) bool) bool {
+	for currIndex, currEntry := range doc.Entries {
+		if !callback(doc, currIndex) {
+			return false
+		}
+
+		for _, currLine := range currEntry.Lines {
+			for _, includedDoc := range files.Includes[currLine] {
+				if !files.walkDoc(includedDoc, callback) {
+					return false
+				}
+			}
+		}
+	}
+	return true
+}
+
+// Finds the first Host/Match block in any file that goes by the given name
+// Returns the file the block is in, its index in that file and the block itself, or nil, -1 and nil if there is no match
+func (files *ConfigFiles) FindBlock(name string, includeCommented bool) (*ConfigDocument, int, *ConfigEntry) {
+	var foundDoc *ConfigDocument
+	foundIndex := -1
+
+	files.WalkEntries(func(doc *ConfigDocument, index int) bool {
+		currEntry := doc.Entries[index]
+		if !currEntry.IsBlock() || (currEntry.IsCommented() && !includeCommented) || !currEntry.IsNamed(name) {
+			return true
+		}
+		foundDoc = doc
+		foundIndex = index
+		return false
+	})
+
+	if foundDoc == nil {
+		return nil, -1, nil
+	}
+	return foundDoc, foundIndex, foundDoc.Entries[foundIndex]
+}
+
+// Finds the given sub header that lives under the given main header in any file
+// Returns the file the header is in and its index in that file, or nil and -1 if the headers do not exist
+func (files *ConfigFiles) FindSubHeader(mainHeader string, subHeader string) (*ConfigDocument, int) {
+	for _, currDoc := range files.Docs {
+		if subHeaderIndex := currDoc.FindSubHeader(mainHeader, subHeader); subHeaderIndex != -1 {
+			return currDoc, subHeaderIndex
+		}
+	}
+	return nil, -1
+}
+
+// Gets every file that was changed since it was read
+func (files *ConfigFiles) GetChangedDocs() []*ConfigDocument {
+	changedDocs := []*ConfigDocument{}
+	for _, currDoc := range files.Docs {
+		if currDoc.HasChanged() {
+			changedDocs = append(changedDocs, currDoc)
+		}
+	}
+	return changedDocs
+}
+
+// Gets the Include lines of an entry that are not commented out
+func (entry *ConfigEntry) GetIncludeLines() []*ConfigLine {
+	includeLines := []*ConfigLine{}
+	for _, currLine := range entry.Lines {
+		if currLine.Kind == OptionLine && !currLine.Commented && strings.EqualFold(currLine.Key, "Include") {
+			includeLines = append(includeLines, currLine)
+		}
+	}
+	return includeLines
+}
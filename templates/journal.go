@@ -0,0 +1,19 @@
+package sshmkr_templates
+
+// Data struct that holds a single command that changed one or more config files
+type JournalEntry struct {
+	Command string				`json:"command"`
+	Args []string				`json:"args"`
+	Timestamp string			`json:"timestamp"`
+	Files []JournalFileChange	`json:"files"`
+	Undone bool					`json:"undone"`
+}
+
+// Data struct that holds the change that a command made to a single config file
+type JournalFileChange struct {
+	Path string			`json:"path"`
+	BeforeHash string	`json:"before_hash"`
+	AfterHash string	`json:"after_hash"`
+	Diff string			`json:"diff"`
+	Before string		`json:"before"`	// Full contents before the change, used to undo it
+}
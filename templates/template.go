@@ -1,6 +1,9 @@
 package sshmkr_templates
 
 import (
+	"fmt"
+	"regexp"
+	"strings"
 	"github.com/kevinburke/ssh_config"
 )
 
@@ -8,17 +11,33 @@ import (
 type HeaderBlock struct {
 	MainHeader string
 	SubHeaders []string
+	Hosts [][]HostSummary	// Hosts that live under each sub header, in the same order as SubHeaders
+	File string				// Location of the config file that the headers are in
+}
+
+// Data struct that holds a short summary of a host config
+type HostSummary struct {
+	Name string
+	Hostname string
+	User string
+	Port string
+	Commented bool
 }
 
 // Data struct that holds information regarding templated values
 type ConfigTemplate struct {
 	KeyPairs []ssh_config.KV
-	FormattedString string
+	Annotations []KeyAnnotation	// Annotations of each key pair, in the same order as KeyPairs
+	ExpandVariables bool		// Fill in the {{.Name}} and ${NAME} placeholders of the values, which is only done for templates in config_templates
 }
 
-// Returns a templated string that represents the config
-func (temp ConfigTemplate) GetTemplatedString() string {
-	return temp.FormattedString
+// Data struct that holds the annotations of a template key, which are the "# @name value" comments written above it
+type KeyAnnotation struct {
+	Prompt string		// Text to ask for the value with, instead of "Enter a value for Key"
+	Required bool		// The value cannot be left empty
+	Choices []string	// The only values that can be used, if any
+	Pattern string		// Regular expression that the whole value has to match, if any
+	Fixed bool			// The value of the template is always used, and is never prompted for
 }
 
 // Returns a specific key pair from the template
@@ -27,6 +46,54 @@ func (temp ConfigTemplate) GetKeyPair(index int) ssh_config.KV {
 	return temp.KeyPairs[index]	
 }
 
+// Gets the annotations of a specific key pair, which are empty if the key does not have any
+func (temp ConfigTemplate) GetKeyAnnotation(index int) KeyAnnotation {
+	if index < len(temp.Annotations) {
+		return temp.Annotations[index]
+	}
+	return KeyAnnotation{}
+}
+
+// Checks that a value can be used for the key that the annotations belong to
+// Returns an ErrInvalidInput error that says what is wrong with the value
+func (annotation KeyAnnotation) Validate(key string, value string) error {
+	if annotation.Required && value == "" {
+		return NewConfigError(ErrInvalidInput, "A value is required for", key, "!")
+	} else if value == "" {
+		return nil
+	}
+
+	if len(annotation.Choices) > 0 {
+		isChoice := false
+		for _, currChoice := range annotation.Choices {
+			isChoice = isChoice || currChoice == value
+		}
+		if !isChoice {
+			return NewConfigError(ErrInvalidInput, fmt.Sprintf("%s is not a valid value for %s! Available values are: [%s]", value, key, strings.Join(annotation.Choices, ", ")))
+		}
+	}
+	if annotation.Pattern != "" {
+		if isMatch, err := regexp.MatchString("^(?:" + annotation.Pattern + ")$", value); err != nil || !isMatch {
+			return NewConfigError(ErrInvalidInput, fmt.Sprintf("%s is not a valid value for %s! It has to match the pattern %s", value, key, annotation.Pattern))
+		}
+	}
+	return nil
+}
+
+// Builds the host config of the template with the given value for each of its key pairs
+// The first key pair is the Host/Match line, and every other one is an option under it
+func (temp ConfigTemplate) Render(values []string) string {
+	renderedString := ""
+	for currIndex, currKeyPair := range temp.KeyPairs {
+		if currIndex == 0 {
+			renderedString = "\n" + currKeyPair.Key + " " + values[currIndex] + "\n"
+		} else {
+			renderedString = renderedString + "\t" + currKeyPair.Key + " " + values[currIndex] + "\n"
+		}
+	}
+	return renderedString
+}
+
 // Gets the number of key pairs that are in the template
 func (temp ConfigTemplate) GetNumKeyPairs() int {
 	return len(temp.KeyPairs)
@@ -40,4 +107,99 @@ func (header HeaderBlock) GetMainHeader() string {
 // Gets the sub headers for that block
 func (header HeaderBlock) GetSubHeaders() []string {
 	return header.SubHeaders
-}
\ No newline at end of file
+}
+
+// Gets the hosts that live under a given sub header in that block
+func (header HeaderBlock) GetSubHeaderHosts(index int) []HostSummary {
+	if index < len(header.Hosts) {
+		return header.Hosts[index]
+	}
+	return []HostSummary{}
+}
+
+// Data struct that holds the answers that were given from the command line instead of being prompted for
+type InputOptions struct {
+	SetValues []ssh_config.KV	// Values for template keys, in the order they were given
+	HeaderPath string			// "Main Header/Sub Header" path to place a new host under
+	AcceptDefaults bool			// Use the default value of a key instead of prompting for it
+	NonInteractive bool			// Never prompt, and treat anything that is missing as an error
+	HistoryLoc string			// File that the typed in values are remembered in, so they can be brought back with the up arrow
+	AnswersLoc string			// YAML file with the header and values of one or more hosts
+}
+
+// Data struct that holds the answers for a single host that were read from an answers file
+type HostAnswers struct {
+	Source string				// Template or host to use instead of the -source flag, if set
+	HeaderPath string			// "Main Header/Sub Header" path to place the host under
+	Values []ssh_config.KV		// Values for the keys of the host, in the order they were written
+}
+
+// Adds the answers to the options that were passed in from the command line
+// Anything that was passed in as a flag is kept over the answers, and keys without an answer use their default
+func (answers HostAnswers) ApplyTo(options InputOptions) InputOptions {
+	appliedOptions := options
+	appliedOptions.SetValues = append([]ssh_config.KV{}, options.SetValues...)
+	for _, currValue := range answers.Values {
+		if _, wasSet := options.GetSetValue(currValue.Key); !wasSet {
+			appliedOptions.SetValues = append(appliedOptions.SetValues, currValue)
+		}
+	}
+	if appliedOptions.HeaderPath == "" {
+		appliedOptions.HeaderPath = answers.HeaderPath
+	}
+	appliedOptions.AcceptDefaults = true
+	return appliedOptions
+}
+
+// Gets the value that was set for a given key, and if one was set at all
+func (options InputOptions) GetSetValue(key string) (string, bool) {
+	for _, currValue := range options.SetValues {
+		if strings.EqualFold(currValue.Key, key) {
+			return currValue.Value, true
+		}
+	}
+	return "", false
+}
+
+// Gets every value that was set for a given key, in the order they were set
+// Keys like IdentityFile can be set more than once, either with a list in an answers file or with -set repeated
+func (options InputOptions) GetSetValues(key string) []string {
+	setValues := []string{}
+	for _, currValue := range options.SetValues {
+		if strings.EqualFold(currValue.Key, key) {
+			setValues = append(setValues, currValue.Value)
+		}
+	}
+	return setValues
+}
+
+// Data struct that holds everything about a host config, used for structured output
+type HostDetails struct {
+	Kind string				`json:"kind" yaml:"kind"`	// Either host or match
+	Name string				`json:"name" yaml:"name"`
+	Patterns []string		`json:"patterns" yaml:"patterns"`
+	Criteria string			`json:"criteria,omitempty" yaml:"criteria,omitempty"`	// Only set for Match blocks
+	Options []HostOption	`json:"options" yaml:"options"`
+	MainHeader string		`json:"main_header" yaml:"main_header"`
+	SubHeader string		`json:"sub_header" yaml:"sub_header"`
+	StartLine int			`json:"start_line" yaml:"start_line"`
+	EndLine int				`json:"end_line" yaml:"end_line"`
+	Commented bool			`json:"commented" yaml:"commented"`
+	File string				`json:"file" yaml:"file"`
+}
+
+// Data struct that holds a single key/value of a host config, used for structured output
+type HostOption struct {
+	Key string		`json:"key" yaml:"key"`
+	Value string	`json:"value" yaml:"value"`
+	Comment string	`json:"comment,omitempty" yaml:"comment,omitempty"`
+}
+
+// Data struct that holds a single problem that was found in a ssh config, used by the lint command
+type LintIssue struct {
+	Rule string		`json:"rule" yaml:"rule"`
+	File string		`json:"file" yaml:"file"`
+	Line int		`json:"line" yaml:"line"`
+	Name string		`json:"name,omitempty" yaml:"name,omitempty"`	// The Host/Match block the problem is in, if any
+	Message string	`json:"message" yaml:"message"`
+}
@@ -0,0 +1,53 @@
+package sshmkr_templates
+
+import (
+	"os"
+	"regexp"
+	"strings"
+)
+
+// Constants
+const HEADER_VARIABLE = "Header"			// The "Main Header/Sub Header" path that the host is placed under
+const MAIN_HEADER_VARIABLE = "MainHeader"
+const SUB_HEADER_VARIABLE = "SubHeader"
+
+// Placeholders that are filled in with the value of an earlier key or a variable, i.e {{.Host}}
+var templateVariableRegex = regexp.MustCompile(`\{\{\s*\.([A-Za-z_][A-Za-z0-9_]*)\s*\}\}`)
+
+// Placeholders that are filled in with an environment variable, i.e ${USER}
+var environmentVariableRegex = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)\}`)
+
+// Gets the names of the {{.Name}} variables that a template value uses, in the order they are used
+func GetVariableNames(value string) []string {
+	variableNames := []string{}
+	for _, currMatch := range templateVariableRegex.FindAllStringSubmatch(value, -1) {
+		variableNames = append(variableNames, currMatch[1])
+	}
+	return variableNames
+}
+
+// Fills in the {{.Name}} variables and ${NAME} environment variables that a template value uses
+// Variables are looked up by their lowercased name, and using one that does not have a value yet is an error
+// Environment variables that are not set are left as they are, so ssh can still fill them in when it connects
+func ExpandVariables(value string, variables map[string]string) (string, error) {
+	var missingName string
+	expandedValue := templateVariableRegex.ReplaceAllStringFunc(value, func(placeholder string) string {
+		variableName := templateVariableRegex.FindStringSubmatch(placeholder)[1]
+		variableValue, hasValue := variables[strings.ToLower(variableName)]
+		if !hasValue && missingName == "" {
+			missingName = variableName
+		}
+		return variableValue
+	})
+	if missingName != "" {
+		return "", NewConfigError(ErrInvalidInput, "{{." + missingName + "}} does not have a value yet! A template value can only use the keys above it, the header and its own variables.")
+	}
+
+	expandedValue = environmentVariableRegex.ReplaceAllStringFunc(expandedValue, func(placeholder string) string {
+		if envValue, isSet := os.LookupEnv(environmentVariableRegex.FindStringSubmatch(placeholder)[1]); isSet {
+			return envValue
+		}
+		return placeholder
+	})
+	return expandedValue, nil
+}
@@ -0,0 +1,649 @@
+package sshmkr_ui
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"sshmkr/commands"
+	"sshmkr/input"
+	"sshmkr/reader"
+	"sshmkr/templates"
+)
+
+// Constants
+const CLEAR_SCREEN = "\x1b[H\x1b[2J"
+const HIDE_CURSOR = "\x1b[?25l"
+const SHOW_CURSOR = "\x1b[?25h"
+const REVERSE_TEXT = "\x1b[7m"
+const BOLD_TEXT = "\x1b[1m"
+const RESET_TEXT = "\x1b[0m"
+const KEY_HELP = "up/down select  / filter  enter show  e edit  c copy  x comment  d delete  m move  q quit"
+
+// Data struct that holds a single row of the header tree
+type treeRow struct {
+	Kind sshmkr_templates.LineKind	// MainHeaderLine, SubHeaderLine, or HostLine/MatchLine for hosts
+	Text string
+	Doc *sshmkr_templates.ConfigDocument
+	Index int						// Index of the host in its file, only set for hosts
+}
+
+// Data struct that holds the state of the interface
+type hostBrowser struct {
+	configLoc string
+	save func(cmdArgs []string, files *sshmkr_templates.ConfigFiles) error
+	files *sshmkr_templates.ConfigFiles
+	rows []treeRow			// Every row of the tree
+	visibleRows []treeRow	// The rows that are left after filtering
+	cursor int				// Index of the selected host in visibleRows, or -1 if no host is shown
+	scroll int				// Index of the first row of visibleRows that is drawn
+	filter string
+	filtering bool			// Whether key presses are typed into the filter
+	status string			// Message shown above the key help until the next key press
+	restore func()			// Puts the terminal back into the mode it was in before the interface started
+	fatalErr error			// Error that the interface cannot keep going after, i.e the config could not be read again
+}
+
+// Starts up a full screen interface that shows the header tree on the left and the selected host on the right
+// Hosts are changed with the same functions as the other commands, and every change is saved with the passed in save function
+// Errors from changing a host are shown in the interface, and only errors it cannot keep going after are returned
+func RunUI(configLoc string, save func(cmdArgs []string, files *sshmkr_templates.ConfigFiles) error) error {
+	files, err := sshmkr_reader.ReadConfigFiles(configLoc)
+	if err != nil {
+		return err
+	}
+	restore, err := sshmkr_input.EnableRawMode()
+	if err != nil {
+		return sshmkr_templates.NewConfigError(sshmkr_templates.ErrFileAccess, err)
+	}
+
+	browser := &hostBrowser{configLoc: configLoc, save: save, files: files, restore: restore}
+	browser.rows = buildTreeRows(files)
+	browser.applyFilter("")
+	fmt.Print(HIDE_CURSOR)
+
+	for browser.fatalErr == nil {
+		browser.render()
+		if !browser.handleKey(readKey()) {
+			break
+		}
+	}
+
+	if browser.restore != nil {
+		browser.restore()
+	}
+	fmt.Print(CLEAR_SCREEN + SHOW_CURSOR)
+	return browser.fatalErr
+}
+
+// Helper method that reads a single key press, which can be more than one byte for keys like the arrows
+func readKey() string {
+	keyBuffer := make([]byte, 16)
+	readCount, err := os.Stdin.Read(keyBuffer)
+	if err != nil {
+		return "\x03"
+	}
+	return string(keyBuffer[:readCount])
+}
+
+// Helper method that acts on a key press
+// Returns false if the interface should be closed
+func (browser *hostBrowser) handleKey(key string) bool {
+	browser.status = ""
+
+	if browser.filtering {
+		switch key {
+			case "\r", "\n", "\x1b":
+				browser.filtering = false
+			case "\x7f", "\b":
+				if filterRunes := []rune(browser.filter); len(filterRunes) > 0 {
+					browser.filter = string(filterRunes[:len(filterRunes)-1])
+				}
+			case "\x03":
+				return false
+			case "\x1b[A":
+				browser.moveCursor(-1)
+			case "\x1b[B":
+				browser.moveCursor(1)
+			default:
+				if key[0] >= ' ' && key[0] != '\x7f' && !strings.HasPrefix(key, "\x1b") {
+					browser.filter = browser.filter + key
+				}
+		}
+		browser.applyFilter("")
+		return true
+	}
+
+	_, bodyHeight := browser.getPaneSizes()
+	switch key {
+		case "q", "\x03":
+			return false
+		case "\x1b[A", "k":
+			browser.moveCursor(-1)
+		case "\x1b[B", "j":
+			browser.moveCursor(1)
+		case "\x1b[5~":
+			browser.moveCursor(-bodyHeight)
+		case "\x1b[6~":
+			browser.moveCursor(bodyHeight)
+		case "/":
+			browser.filtering = true
+		case "\x1b":
+			browser.filter = ""
+			browser.applyFilter("")
+		case "\r", "\n", "s":
+			browser.showHost()
+		case "e":
+			browser.editHost()
+		case "c":
+			browser.copyHost()
+		case "x":
+			browser.commentHost()
+		case "d":
+			browser.deleteHost()
+		case "m":
+			browser.moveHost()
+	}
+	return true
+}
+
+// Helper method that reads the config again and rebuilds the tree, selecting the host with the given name if it is shown
+// Since the hosts were already changed in memory, a failed change is undone by reloading as well
+func (browser *hostBrowser) reload(selectName string) {
+	files, err := sshmkr_reader.ReadConfigFiles(browser.configLoc)
+	if err != nil {
+		browser.fatalErr = err
+		return
+	}
+	browser.files = files
+	browser.rows = buildTreeRows(browser.files)
+	browser.applyFilter(selectName)
+}
+
+// Helper method that builds the rows of the header tree out of every file of the config
+func buildTreeRows(files *sshmkr_templates.ConfigFiles) []treeRow {
+	rows := []treeRow{}
+	for _, currDoc := range files.Docs {
+		fileLabel := ""
+		if currDoc != files.GetMainDoc() {
+			fileLabel = fmt.Sprintf("  (%s)", currDoc.Path)
+		}
+
+		seenHeader := false
+		for currIndex, currEntry := range currDoc.Entries {
+			switch {
+				case currEntry.Kind == sshmkr_templates.MainHeaderLine:
+					seenHeader = true
+					rows = append(rows, treeRow{Kind: currEntry.Kind, Text: sshmkr_reader.TrimHeaderIndicator(currEntry.Lines[0].Raw) + fileLabel, Doc: currDoc})
+				case currEntry.Kind == sshmkr_templates.SubHeaderLine:
+					seenHeader = true
+					rows = append(rows, treeRow{Kind: currEntry.Kind, Text: sshmkr_reader.TrimHeaderIndicator(currEntry.Lines[0].Raw), Doc: currDoc})
+				case currEntry.IsBlock():
+					// Hosts that are placed before any header are grouped together, the same way the list command does
+					if !seenHeader {
+						seenHeader = true
+						rows = append(rows, treeRow{Kind: sshmkr_templates.MainHeaderLine, Text: "(no header)" + fileLabel, Doc: currDoc})
+					}
+
+					hostText := currEntry.GetName()
+					if currEntry.IsCommented() {
+						hostText = hostText + " (commented)"
+					}
+					rows = append(rows, treeRow{Kind: currEntry.Kind, Text: hostText, Doc: currDoc, Index: currIndex})
+			}
+		}
+	}
+	return rows
+}
+
+// Helper method that only keeps the hosts that match the filter, along with the headers above them
+// The host with the given name is selected if it is shown, otherwise the cursor stays as close to where it was as it can
+func (browser *hostBrowser) applyFilter(selectName string) {
+	prevCursor := browser.cursor
+	if selectName == "" && browser.getSelectedHost() != nil {
+		selectName = browser.getSelectedHost().GetName()
+	}
+
+	browser.visibleRows = []treeRow{}
+	pendingHeaders := []treeRow{}
+	for _, currRow := range browser.rows {
+		switch currRow.Kind {
+			case sshmkr_templates.MainHeaderLine:
+				pendingHeaders = []treeRow{currRow}
+			case sshmkr_templates.SubHeaderLine:
+				if len(pendingHeaders) > 0 && pendingHeaders[len(pendingHeaders)-1].Kind == sshmkr_templates.SubHeaderLine {
+					pendingHeaders = pendingHeaders[:len(pendingHeaders)-1]
+				}
+				pendingHeaders = append(pendingHeaders, currRow)
+			default:
+				if !matchesFilter(currRow.Doc.Entries[currRow.Index], browser.filter) {
+					continue
+				}
+				browser.visibleRows = append(browser.visibleRows, pendingHeaders...)
+				browser.visibleRows = append(browser.visibleRows, currRow)
+				pendingHeaders = []treeRow{}
+		}
+	}
+
+	// Headers are always shown when there is no filter, even if they are empty
+	if browser.filter == "" {
+		browser.visibleRows = browser.rows
+	}
+
+	browser.cursor = -1
+	for currIndex, currRow := range browser.visibleRows {
+		if isHostRow(currRow) && currRow.Doc.Entries[currRow.Index].GetName() == selectName {
+			browser.cursor = currIndex
+			return
+		}
+	}
+	browser.cursor = prevCursor
+	if browser.cursor >= len(browser.visibleRows) {
+		browser.cursor = len(browser.visibleRows) - 1
+	}
+	if browser.cursor < 0 {
+		browser.cursor = 0
+	}
+	browser.moveCursor(0)
+}
+
+// Helper method that moves the cursor by the given number of rows, skipping over headers
+func (browser *hostBrowser) moveCursor(delta int) {
+	if len(browser.visibleRows) == 0 {
+		browser.cursor = -1
+		return
+	}
+
+	step := 1
+	if delta < 0 {
+		step = -1
+	}
+	newCursor := browser.cursor + delta
+	if newCursor < 0 {
+		newCursor = 0
+	} else if newCursor >= len(browser.visibleRows) {
+		newCursor = len(browser.visibleRows) - 1
+	}
+
+	// We look for a host in the direction we moved in first, and then in the other direction
+	for _, currStep := range []int{step, -step} {
+		for currIndex := newCursor; currIndex >= 0 && currIndex < len(browser.visibleRows); currIndex = currIndex + currStep {
+			if isHostRow(browser.visibleRows[currIndex]) {
+				browser.cursor = currIndex
+				return
+			}
+		}
+	}
+	browser.cursor = -1
+}
+
+// Helper method that gets the host that the cursor is on, or nil if there is none
+func (browser *hostBrowser) getSelectedHost() *sshmkr_templates.ConfigEntry {
+	if browser.cursor < 0 || browser.cursor >= len(browser.visibleRows) || !isHostRow(browser.visibleRows[browser.cursor]) {
+		return nil
+	}
+	selectedRow := browser.visibleRows[browser.cursor]
+	return selectedRow.Doc.Entries[selectedRow.Index]
+}
+
+// Helper method that gets the width of the tree pane and the number of rows that the panes have
+func (browser *hostBrowser) getPaneSizes() (int, int) {
+	termRows, termCols := sshmkr_input.GetTerminalSize()
+	treeWidth := termCols * 2 / 5
+	if treeWidth < 20 {
+		treeWidth = 20
+	}
+	return treeWidth, termRows - 3
+}
+
+// Helper method that draws the whole interface
+func (browser *hostBrowser) render() {
+	_, termCols := sshmkr_input.GetTerminalSize()
+	treeWidth, bodyHeight := browser.getPaneSizes()
+	detailWidth := termCols - treeWidth - 3
+
+	// The tree is scrolled so that the cursor is always on screen
+	if browser.cursor != -1 && browser.cursor < browser.scroll {
+		browser.scroll = browser.cursor
+	} else if browser.cursor >= browser.scroll + bodyHeight {
+		browser.scroll = browser.cursor - bodyHeight + 1
+	}
+	if browser.scroll > len(browser.visibleRows) - bodyHeight {
+		browser.scroll = len(browser.visibleRows) - bodyHeight
+	}
+	if browser.scroll < 0 {
+		browser.scroll = 0
+	}
+
+	titleLine := fmt.Sprintf("sshmkr  %s", browser.configLoc)
+	if browser.filtering || browser.filter != "" {
+		titleLine = fmt.Sprintf("%s    Filter: %s", titleLine, browser.filter)
+		if browser.filtering {
+			titleLine = titleLine + "_"
+		}
+	}
+
+	output := CLEAR_SCREEN + BOLD_TEXT + fitWidth(titleLine, termCols) + RESET_TEXT + "\r\n"
+	detailLines := browser.getDetailLines()
+	for lineNum := 0; lineNum < bodyHeight; lineNum = lineNum + 1 {
+		treeText := fitWidth("", treeWidth)
+		if rowIndex := browser.scroll + lineNum; rowIndex < len(browser.visibleRows) {
+			treeText = formatTreeRow(browser.visibleRows[rowIndex], treeWidth, rowIndex == browser.cursor)
+		}
+
+		detailText := ""
+		if lineNum < len(detailLines) {
+			detailText = fitWidth(detailLines[lineNum], detailWidth)
+		}
+		output = output + treeText + " │ " + detailText + "\r\n"
+	}
+
+	output = output + fitWidth(browser.status, termCols) + "\r\n"
+	output = output + REVERSE_TEXT + fitWidth(KEY_HELP, termCols) + RESET_TEXT
+	fmt.Print(output)
+}
+
+// Helper method that gets the lines that describe the selected host
+func (browser *hostBrowser) getDetailLines() []string {
+	if browser.cursor == -1 {
+		return []string{"No hosts to show"}
+	}
+
+	selectedRow := browser.visibleRows[browser.cursor]
+	details := sshmkr_commands.GetHostDetails(selectedRow.Index, selectedRow.Doc)
+	headerPath := "no header"
+	if details.MainHeader != "" {
+		headerPath = details.MainHeader
+	}
+	if details.SubHeader != "" {
+		headerPath = headerPath + "/" + details.SubHeader
+	}
+
+	detailLines := []string{
+		BOLD_TEXT + details.Name + RESET_TEXT,
+		"Header: " + headerPath,
+		fmt.Sprintf("File:   %s:%d-%d", details.File, details.StartLine, details.EndLine),
+		"",
+	}
+	for _, currLine := range selectedRow.Doc.Entries[selectedRow.Index].GetAllLines() {
+		detailLines = append(detailLines, strings.Replace(currLine.Raw, "\t", "    ", -1))
+	}
+	return detailLines
+}
+
+// Helper method that formats a row of the tree, indented by its level and padded to the width of the pane
+func formatTreeRow(row treeRow, width int, isSelected bool) string {
+	switch row.Kind {
+		case sshmkr_templates.MainHeaderLine:
+			return BOLD_TEXT + fitWidth(row.Text, width) + RESET_TEXT
+		case sshmkr_templates.SubHeaderLine:
+			return BOLD_TEXT + fitWidth("  " + row.Text, width) + RESET_TEXT
+	}
+
+	rowText := fitWidth("    " + row.Text, width)
+	if isSelected {
+		return REVERSE_TEXT + rowText + RESET_TEXT
+	}
+	return rowText
+}
+
+// Helper method that cuts off or pads a line so that it is exactly the given number of characters wide
+// Escape codes at the start and end of the text are not counted towards the width
+func fitWidth(text string, width int) string {
+	if width <= 0 {
+		return ""
+	}
+
+	prefix, suffix := "", ""
+	if strings.HasPrefix(text, BOLD_TEXT) && strings.HasSuffix(text, RESET_TEXT) {
+		prefix, suffix = BOLD_TEXT, RESET_TEXT
+		text = text[len(BOLD_TEXT):len(text)-len(RESET_TEXT)]
+	}
+
+	textRunes := []rune(text)
+	if len(textRunes) > width {
+		textRunes = append(textRunes[:width-1], '…')
+	}
+	return prefix + string(textRunes) + suffix + strings.Repeat(" ", width - len(textRunes))
+}
+
+// Helper method that checks if a row of the tree is a host
+func isHostRow(row treeRow) bool {
+	return row.Kind == sshmkr_templates.HostLine || row.Kind == sshmkr_templates.MatchLine
+}
+
+// Helper method that checks if a host matches the filter by its names, Hostname or User
+func matchesFilter(host *sshmkr_templates.ConfigEntry, filter string) bool {
+	lowerFilter := strings.ToLower(filter)
+	searchedValues := append(host.GetPatterns(), host.GetName(), host.GetOption("Hostname"), host.GetOption("User"))
+	for _, currValue := range searchedValues {
+		if strings.Contains(strings.ToLower(currValue), lowerFilter) {
+			return true
+		}
+	}
+	return false
+}
+
+// Helper method that gives the terminal back to a command that prompts the user, and goes back into the interface after
+// Returns false if the command failed, after showing why in the status line
+func (browser *hostBrowser) runPrompted(action func() error) bool {
+	browser.restore()
+	browser.restore = nil
+	fmt.Print(CLEAR_SCREEN + SHOW_CURSOR)
+
+	// Values typed in for an action that was aborted or failed are never written to the history
+	sshmkr_input.DiscardPendingHistory()
+	err := action()
+	browser.enterRawMode()
+	if err != nil {
+		browser.status = strings.Replace(err.Error(), "\n", " ", -1)
+		return false
+	}
+	return true
+}
+
+// Helper method that saves the changes with the terminal out of raw mode, so it is left usable while the changes are shown or confirmed
+// Returns false if nothing was saved, i.e when the config was changed by something else while the interface was open
+func (browser *hostBrowser) saveChanges(cmdArgs []string) bool {
+	return browser.runPrompted(func() error {
+		return browser.save(cmdArgs, browser.files)
+	})
+}
+
+// Helper method that puts the terminal back into raw mode after it was given back to a command
+func (browser *hostBrowser) enterRawMode() {
+	restore, err := sshmkr_input.EnableRawMode()
+	if err != nil {
+		browser.fatalErr = sshmkr_templates.NewConfigError(sshmkr_templates.ErrFileAccess, err)
+		return
+	}
+	browser.restore = restore
+	fmt.Print(HIDE_CURSOR)
+}
+
+// Helper method that gets the selected host, setting a message if there is none or it is commented out
+// Only the comment and show actions work on commented hosts, the same as the other commands
+func (browser *hostBrowser) getActionHost(allowCommented bool) *sshmkr_templates.ConfigEntry {
+	host := browser.getSelectedHost()
+	if host == nil {
+		browser.status = "No host is selected!"
+		return nil
+	} else if host.IsCommented() && !allowCommented {
+		browser.status = fmt.Sprintf("Host %s is commented out! Uncomment it first with x.", host.GetName())
+		return nil
+	}
+	return host
+}
+
+// Helper method that prints out the selected host the same way the show command does
+func (browser *hostBrowser) showHost() {
+	host := browser.getActionHost(true)
+	if host == nil {
+		return
+	}
+
+	browser.runPrompted(func() error {
+		err := sshmkr_commands.GetSpecificHostConfig(host.GetName(), host.IsCommented(), "text", browser.files)
+		if err != nil {
+			return err
+		}
+		fmt.Print("\nPress enter to go back...")
+		waitForEnter()
+		return nil
+	})
+}
+
+// Helper method that edits the selected host the same way the edit command does
+func (browser *hostBrowser) editHost() {
+	host := browser.getActionHost(false)
+	if host == nil {
+		return
+	}
+
+	hostName := host.GetName()
+	newHostName := hostName
+	hasEdited := browser.runPrompted(func() error {
+		template, err := sshmkr_reader.ReadSpecificTemplate(hostName, browser.files)
+		if err != nil {
+			return err
+		}
+		editedConfig, editedName, err := sshmkr_input.InterpolateUserInput(template, sshmkr_templates.InputOptions{HistoryLoc: sshmkr_reader.GetInputHistoryLoc(browser.configLoc)})
+		if err != nil {
+			return err
+		}
+		if err = sshmkr_commands.EditExisingConfig(hostName, editedConfig, browser.files); err != nil {
+			return err
+		}
+		if err = browser.save([]string{"edit", "-source", hostName}, browser.files); err != nil {
+			return err
+		}
+		newHostName = getFirstName(editedName)
+		return nil
+	})
+	browser.reloadAfter(hasEdited, newHostName, fmt.Sprintf("Sucesfully edited host config, %s !", hostName))
+}
+
+// Helper method that copies the selected host into a new one the same way the copy command does
+func (browser *hostBrowser) copyHost() {
+	host := browser.getActionHost(false)
+	if host == nil {
+		return
+	}
+
+	hostName := host.GetName()
+	newHostName := ""
+	hasCopied := browser.runPrompted(func() error {
+		template, err := sshmkr_reader.ReadSpecificTemplate(hostName, browser.files)
+		if err != nil {
+			return err
+		}
+		headers := sshmkr_reader.ParseConfigHeaders(browser.files)
+		mainHeader, subHeader, err := sshmkr_input.SelectNewConfigLoc(headers, sshmkr_templates.InputOptions{})
+		if err != nil {
+			return err
+		}
+		userAddedConfig, addedName, err := sshmkr_input.InterpolateUserInput(template, sshmkr_templates.InputOptions{HistoryLoc: sshmkr_reader.GetInputHistoryLoc(browser.configLoc)})
+		if err != nil {
+			return err
+		}
+		if err = sshmkr_commands.AddTemplatedConfig(mainHeader, subHeader, userAddedConfig, browser.files); err != nil {
+			return err
+		}
+		if err = browser.save([]string{"copy", "-source", hostName}, browser.files); err != nil {
+			return err
+		}
+		newHostName = getFirstName(addedName)
+		return nil
+	})
+	browser.reloadAfter(hasCopied, newHostName, fmt.Sprintf("Sucessfuly created new host %s from %s !", newHostName, hostName))
+}
+
+// Helper method that comments in/out the selected host the same way the comment command does
+func (browser *hostBrowser) commentHost() {
+	host := browser.getActionHost(true)
+	if host == nil {
+		return
+	}
+
+	hostName := host.GetName()
+	hasCommented := sshmkr_commands.ToggleEntryComment(host)
+	if hasCommented {
+		browser.reloadAfter(browser.saveChanges([]string{"comment", "-source", hostName}), hostName, fmt.Sprintf("Sucessfully commented out host %s !", hostName))
+	} else {
+		browser.reloadAfter(browser.saveChanges([]string{"comment", "-source", hostName}), hostName, fmt.Sprintf("Sucessfully uncommented out host %s !", hostName))
+	}
+}
+
+// Helper method that removes the selected host the same way the delete command does, once the user confirms it
+func (browser *hostBrowser) deleteHost() {
+	host := browser.getActionHost(false)
+	if host == nil {
+		return
+	}
+
+	hostName := host.GetName()
+	browser.status = fmt.Sprintf("Remove host %s from the ssh_config? [y/N]", hostName)
+	browser.render()
+	if readKey() != "y" {
+		browser.status = "Nothing was removed."
+		return
+	}
+
+	selectedRow := browser.visibleRows[browser.cursor]
+	sshmkr_commands.RemoveEntryWithSpacing(selectedRow.Index, selectedRow.Doc)
+	browser.reloadAfter(browser.saveChanges([]string{"delete", "-source", hostName}), "", fmt.Sprintf("Sucessfully removed host %s from ssh_config!", hostName))
+}
+
+// Helper method that moves the selected host under another header the same way the move command does
+func (browser *hostBrowser) moveHost() {
+	host := browser.getActionHost(false)
+	if host == nil {
+		return
+	}
+
+	hostName := host.GetName()
+	headerPath := ""
+	hasMoved := browser.runPrompted(func() error {
+		headers := sshmkr_reader.ParseConfigHeaders(browser.files)
+		mainHeader, subHeader, err := sshmkr_input.SelectNewConfigLoc(headers, sshmkr_templates.InputOptions{})
+		if err != nil {
+			return err
+		}
+		if err = sshmkr_commands.MoveHostConfig(hostName, mainHeader, subHeader, browser.files); err != nil {
+			return err
+		}
+
+		headerPath = sshmkr_reader.TrimHeaderIndicator(mainHeader) + "/" + sshmkr_reader.TrimHeaderIndicator(subHeader)
+		return browser.save([]string{"move", "-source", hostName, "-to", headerPath}, browser.files)
+	})
+	browser.reloadAfter(hasMoved, hostName, fmt.Sprintf("Sucessfully moved host %s under %s !", hostName, headerPath))
+}
+
+// Helper method that reads the config again after a change, keeping the error message in the status line if the change failed
+func (browser *hostBrowser) reloadAfter(hasChanged bool, selectName string, successMessage string) {
+	failedStatus := browser.status
+	browser.reload(selectName)
+	if hasChanged {
+		browser.status = successMessage
+	} else {
+		browser.status = failedStatus
+	}
+}
+
+// Helper method that gets the first name out of the names that a host was given
+func getFirstName(hostNames string) string {
+	if nameFields := strings.Fields(hostNames); len(nameFields) > 0 {
+		return nameFields[0]
+	}
+	return ""
+}
+
+// Helper method that waits until the user presses enter
+func waitForEnter() {
+	keyBuffer := make([]byte, 1)
+	for {
+		readCount, err := os.Stdin.Read(keyBuffer)
+		if err != nil || (readCount > 0 && keyBuffer[0] == '\n') {
+			return
+		}
+	}
+}